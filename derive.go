@@ -0,0 +1,168 @@
+package observer
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Derive creates a Property whose value is computed by fn from the values of
+// the given source properties, in the same order. The derived property is
+// recomputed whenever any source is updated, and ends once all the sources
+// have ended; it fails with the error of the first source that failed.
+//
+// Recomputation is skipped while the derived property has no active streams:
+// its value is then lazily computed the next time it is read.
+func Derive(fn func(values ...interface{}) interface{}, sources ...Property) Property {
+	return DeriveWith(fn, sources)
+}
+
+// DeriveWith is like Derive but accepts options to configure the derived
+// property, e.g. WithEndOnAnySource or WithHistoryLimit.
+func DeriveWith(fn func(values ...interface{}) interface{}, sources []Property, opts ...Option) Property {
+	d := &derived{
+		fn:      fn,
+		streams: make([]Stream, len(sources)),
+		values:  make([]interface{}, len(sources)),
+	}
+
+	for i, source := range sources {
+		d.streams[i] = source.Observe()
+		d.values[i] = d.streams[i].Value()
+	}
+
+	p := NewPropertyOf(fn(d.values...), opts...).(*property[interface{}])
+	p.onRead = d.recompute
+	d.property = p
+
+	ready := make(chan struct{})
+	go d.run(ready)
+	<-ready
+
+	return p
+}
+
+type derived struct {
+	property *property[interface{}]
+	fn       func(values ...interface{}) interface{}
+
+	mu      sync.Mutex
+	streams []Stream
+	values  []interface{}
+	dirty   bool
+
+	// updating serializes recomputations, so the derived property is updated
+	// in the same order as its values are computed.
+	updating sync.Mutex
+}
+
+// run follows the source streams until they end or the derived property is
+// ended by someone else, the source streams are closed then.
+func (d *derived) run(ready chan struct{}) {
+	defer d.close()
+
+	cases := make([]reflect.SelectCase, len(d.streams)+1)
+	cases[0] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(d.property.Done())}
+
+	for i, s := range d.streams {
+		cases[i+1] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(s.Changes())}
+	}
+
+	close(ready)
+
+	var err error
+	ended := 0
+	for ended < len(d.streams) {
+		chosen, _, _ := reflect.Select(cases)
+		if chosen == 0 {
+			return
+		}
+
+		d.advance()
+
+		for i, s := range d.streams {
+			if !cases[i+1].Chan.IsValid() {
+				continue
+			}
+
+			if !s.Ended() {
+				cases[i+1].Chan = reflect.ValueOf(s.Changes())
+				continue
+			}
+
+			cases[i+1].Chan = reflect.Value{}
+			ended++
+
+			if err == nil {
+				err = s.Err()
+			}
+		}
+
+		if err != nil || (ended > 0 && d.property.opts.endOnAnySource) {
+			break
+		}
+	}
+
+	d.property.Fail(err)
+}
+
+// advance reads every pending value of the source streams and recomputes the
+// derived value if it has any active stream. Sources updated by the same
+// transaction are read together, so they lead to a single recomputation.
+func (d *derived) advance() {
+	commits.RLock()
+	d.mu.Lock()
+
+	for i, s := range d.streams {
+		for s.HasNext() {
+			v := s.Next()
+			if s.Ended() {
+				break
+			}
+
+			d.values[i] = v
+			d.dirty = true
+		}
+	}
+
+	d.mu.Unlock()
+	commits.RUnlock()
+
+	if d.property.observed() {
+		d.recompute()
+	}
+}
+
+// recompute updates the derived property if any source value changed since
+// the last computation, it also brings the derived value up to date before it
+// is read. The derived property is updated once the lock is released, since
+// transactions may hold the lock of the derived property while waiting for
+// commits, which advance holds while waiting for the lock.
+func (d *derived) recompute() {
+	d.updating.Lock()
+	defer d.updating.Unlock()
+
+	d.mu.Lock()
+	dirty := d.dirty
+	d.dirty = false
+
+	var value interface{}
+	if dirty {
+		value = d.fn(d.values...)
+	}
+	d.mu.Unlock()
+
+	if dirty {
+		d.property.Update(value)
+	}
+}
+
+// close closes the source streams, so the sources no longer account for them
+// nor retain the values they were holding.
+func (d *derived) close() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, s := range d.streams {
+		s.Close()
+	}
+}
@@ -0,0 +1,159 @@
+package observer
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+func sum(values ...interface{}) interface{} {
+	total := 0
+	for _, v := range values {
+		total += v.(int)
+	}
+	return total
+}
+
+func TestDeriveInitialValue(t *testing.T) {
+	a := NewProperty(1)
+	b := NewProperty(2)
+	prop := Derive(sum, a, b)
+	if val := prop.Value(); val != 3 {
+		t.Fatalf("Expecting 3 but got %#v\n", val)
+	}
+}
+
+func TestDeriveRecomputes(t *testing.T) {
+	a := NewProperty(1)
+	b := NewProperty(2)
+	prop := Derive(sum, a, b)
+	stream := prop.Observe()
+	a.Update(10)
+	if val := stream.WaitNext(); val != 12 {
+		t.Fatalf("Expecting 12 but got %#v\n", val)
+	}
+	b.Update(20)
+	if val := stream.WaitNext(); val != 30 {
+		t.Fatalf("Expecting 30 but got %#v\n", val)
+	}
+	a.End()
+	b.Update(30)
+	if val := stream.WaitNext(); val != 40 {
+		t.Fatalf("Expecting 40 but got %#v\n", val)
+	}
+	b.End()
+	if val := stream.WaitNext(); val != io.EOF {
+		t.Fatalf("Expecting io.EOF but got %#v\n", val)
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatalf("Expecting no error but got %#v\n", err)
+	}
+}
+
+func TestDeriveSkipsRecomputationWhenNotObserved(t *testing.T) {
+	calls := make(chan struct{}, 100)
+	a := NewProperty(1)
+	prop := Derive(func(values ...interface{}) interface{} {
+		calls <- struct{}{}
+		return values[0].(int) * 2
+	}, a)
+	<-calls
+	for i := 2; i <= 10; i++ {
+		a.Update(i)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if n := len(calls); n != 0 {
+		t.Fatalf("Expecting no recomputation but got %#v\n", n)
+	}
+	if val := prop.Value(); val != 20 {
+		t.Fatalf("Expecting 20 but got %#v\n", val)
+	}
+	if n := len(calls); n != 1 {
+		t.Fatalf("Expecting 1 recomputation but got %#v\n", n)
+	}
+	if val := prop.Value(); val != 20 || len(calls) != 1 {
+		t.Fatalf("Expecting no recomputation but got %#v\n", len(calls))
+	}
+}
+
+func TestDeriveEndOnAnySource(t *testing.T) {
+	a := NewProperty(1)
+	b := NewProperty(2)
+	prop := DeriveWith(sum, []Property{a, b}, WithEndOnAnySource())
+	a.End()
+	select {
+	case <-prop.Done():
+	case <-time.After(time.Second):
+		t.Fatalf("Expecting done\n")
+	}
+}
+
+func TestDeriveFailure(t *testing.T) {
+	failure := errors.New("failure")
+	a := NewProperty(1)
+	b := NewProperty(2)
+	prop := Derive(sum, a, b)
+	b.Fail(failure)
+	select {
+	case <-prop.Done():
+	case <-time.After(time.Second):
+		t.Fatalf("Expecting done\n")
+	}
+	if err := prop.Err(); err != failure {
+		t.Fatalf("Expecting %#v but got %#v\n", failure, err)
+	}
+}
+
+func TestDeriveStopsRecomputingOnceStreamsClose(t *testing.T) {
+	calls := make(chan struct{}, 100)
+	a := NewProperty(1)
+	prop := Derive(func(values ...interface{}) interface{} {
+		calls <- struct{}{}
+		return values[0].(int) * 2
+	}, a)
+	<-calls
+	stream := prop.Observe()
+	a.Update(2)
+	if val := stream.WaitNext(); val != 4 {
+		t.Fatalf("Expecting 4 but got %#v\n", val)
+	}
+	<-calls
+	stream.Close()
+	a.Update(3)
+	time.Sleep(100 * time.Millisecond)
+	if n := len(calls); n != 0 {
+		t.Fatalf("Expecting no recomputation but got %#v\n", n)
+	}
+	if val := prop.Value(); val != 6 {
+		t.Fatalf("Expecting 6 but got %#v\n", val)
+	}
+}
+
+func TestDeriveClosesSourceStreamsOnEnd(t *testing.T) {
+	a := NewProperty(1)
+	b := NewProperty(2)
+	prop := DeriveWith(func(values ...interface{}) interface{} {
+		return values[0].(int) + values[1].(int)
+	}, []Property{a, b})
+	prop.End()
+	for i := 0; i < 10 && a.Stats().Streams+b.Stats().Streams != 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := a.Stats().Streams + b.Stats().Streams; n != 0 {
+		t.Fatalf("Expecting no source streams but got %#v\n", n)
+	}
+
+	c := NewProperty(3)
+	prop = Derive(func(values ...interface{}) interface{} {
+		return values[0]
+	}, c)
+	c.End()
+	<-prop.Done()
+	for i := 0; i < 10 && c.Stats().Streams != 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := c.Stats().Streams; n != 0 {
+		t.Fatalf("Expecting no source streams but got %#v\n", n)
+	}
+}
@@ -1,6 +1,6 @@
 module github.com/botchris/go-observer
 
-go 1.17
+go 1.24
 
 require github.com/stretchr/testify v1.6.1
 
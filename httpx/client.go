@@ -0,0 +1,238 @@
+package httpx
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/botchris/go-observer"
+)
+
+// RemoteError is the error a stream returned by Dial fails with when the
+// remote property fails, see EventError.
+type RemoteError struct {
+	Message string
+}
+
+func (e *RemoteError) Error() string {
+	return "httpx: remote property failed: " + e.Message
+}
+
+// Dial connects to an endpoint served by NewHandler and returns a local stream
+// of the values of the remote property, starting with its current value. The
+// stream ends when the remote property ends, and fails with a RemoteError if
+// it fails, or with the context error once ctx is done. Closing the stream
+// disconnects from the endpoint. It returns ErrEnded if the remote property
+// has already ended, see NewHandler.
+//
+// When the connection drops the client reconnects after the retry delay, see
+// WithRetry, resuming with the Last-Event-ID header from the last value it
+// received. Sequence numbers match the ones of the remote property as long as
+// no values are skipped.
+func Dial[T any](ctx context.Context, url string, opts ...Option) (observer.StreamOf[T], error) {
+	c := &client[T]{
+		url:  url,
+		opts: newOptions(opts),
+	}
+
+	c.retry = c.opts.retry
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	body, err := c.connect(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	events := newEventReader(body, c.opts.maxLine)
+	e, err := events.next()
+	if err != nil {
+		body.Close()
+		cancel()
+		return nil, err
+	}
+
+	var value T
+	if !terminal(e) {
+		if err := c.opts.decoder(e.data, &value); err != nil {
+			body.Close()
+			cancel()
+			return nil, err
+		}
+	}
+
+	seq, _ := strconv.ParseUint(e.id, 10, 64)
+	p := observer.NewPropertyOf(value, observer.WithStartSeq(seq))
+	s := &stream[T]{StreamOf: p.Observe(), cancel: cancel}
+
+	if terminal(e) {
+		c.apply(p, e)
+		body.Close()
+		cancel()
+
+		return s, nil
+	}
+
+	c.lastID = e.id
+	if e.retry > 0 {
+		c.retry = e.retry
+	}
+
+	go c.run(ctx, p, body, events)
+
+	return s, nil
+}
+
+// ErrEnded is returned by Dial when the remote property has already ended, in
+// which case the endpoint responds with 204 No Content.
+var ErrEnded = errors.New("httpx: remote property has ended")
+
+// stream is the stream returned by Dial, closing it disconnects the client.
+type stream[T any] struct {
+	observer.StreamOf[T]
+
+	cancel context.CancelFunc
+}
+
+func (s *stream[T]) Close() {
+	s.StreamOf.Close()
+	s.cancel()
+}
+
+type client[T any] struct {
+	url    string
+	opts   *options
+	lastID string
+	retry  time.Duration
+}
+
+// connect sends the request to the endpoint, resuming from the last event
+// received if any.
+func (c *client[T]) connect(ctx context.Context) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "text/event-stream")
+	if c.lastID != "" {
+		req.Header.Set("Last-Event-ID", c.lastID)
+	}
+
+	resp, err := c.opts.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNoContent {
+		resp.Body.Close()
+		return nil, ErrEnded
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("httpx: unexpected response status %s", resp.Status)
+	}
+
+	return resp.Body, nil
+}
+
+// run updates the local property with the events received, reconnecting when
+// the connection drops, until the remote property ends or ctx is done. The
+// local property ends if the remote one ended while disconnected, there is no
+// telling whether it failed then.
+func (c *client[T]) run(ctx context.Context, p observer.PropertyOf[T], body io.ReadCloser, events *eventReader) {
+	for {
+		if events != nil {
+			done := c.follow(p, events)
+			body.Close()
+
+			if done {
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			p.Fail(ctx.Err())
+			return
+		case <-time.After(c.retry):
+		}
+
+		var err error
+		if body, err = c.connect(ctx); err != nil {
+			if errors.Is(err, ErrEnded) {
+				p.End()
+				return
+			}
+
+			events = nil
+			continue
+		}
+
+		events = newEventReader(body, c.opts.maxLine)
+	}
+}
+
+// follow applies the events received until the connection drops, it reports
+// whether the local property is done. An event too long to be read is not
+// skipped by reconnecting, so the local property fails instead.
+func (c *client[T]) follow(p observer.PropertyOf[T], events *eventReader) bool {
+	for {
+		e, err := events.next()
+		if errors.Is(err, bufio.ErrTooLong) {
+			p.Fail(err)
+			return true
+		}
+
+		if err != nil {
+			return false
+		}
+
+		if e.retry > 0 {
+			c.retry = e.retry
+		}
+
+		if c.apply(p, e) {
+			return true
+		}
+	}
+}
+
+// apply updates the local property with the given event, it reports whether
+// the property is done.
+func (c *client[T]) apply(p observer.PropertyOf[T], e event) bool {
+	switch e.name {
+	case EventEOF:
+		p.End()
+		return true
+	case EventError:
+		p.Fail(&RemoteError{Message: string(e.data)})
+		return true
+	case "", "message":
+		var value T
+		if err := c.opts.decoder(e.data, &value); err != nil {
+			p.Fail(err)
+			return true
+		}
+
+		p.Update(value)
+	}
+
+	if e.id != "" {
+		c.lastID = e.id
+	}
+
+	return false
+}
+
+// terminal reports whether the given event ends the stream.
+func terminal(e event) bool {
+	return e.name == EventEOF || e.name == EventError
+}
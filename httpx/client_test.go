@@ -0,0 +1,198 @@
+package httpx_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/botchris/go-observer"
+	"github.com/botchris/go-observer/httpx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDial(t *testing.T) {
+	t.Run("GIVEN an endpoint WHEN dialed THEN the remote values are streamed until the end", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		prop := observer.NewPropertyOf(point{})
+		prop.Update(point{1, 1})
+
+		server := httptest.NewServer(httpx.NewHandler(prop))
+		defer server.Close()
+
+		stream, err := httpx.Dial[point](ctx, server.URL)
+		require.NoError(t, err)
+		require.Equal(t, point{1, 1}, stream.Value())
+		require.Equal(t, uint64(1), stream.Seq())
+
+		prop.Update(point{2, 2})
+		prop.End()
+
+		require.Equal(t, point{2, 2}, stream.WaitNext())
+		require.Equal(t, uint64(2), stream.Seq())
+
+		stream.WaitNext()
+		require.True(t, stream.Ended())
+		require.NoError(t, stream.Err())
+	})
+
+	t.Run("GIVEN a remote property WHEN it fails THEN the local stream fails with a remote error", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		prop := observer.NewPropertyOf(0)
+		server := httptest.NewServer(httpx.NewHandler(prop))
+		defer server.Close()
+
+		stream, err := httpx.Dial[int](ctx, server.URL)
+		require.NoError(t, err)
+
+		prop.Fail(errors.New("failure"))
+
+		stream.WaitNext()
+		require.True(t, stream.Ended())
+		require.Equal(t, &httpx.RemoteError{Message: "failure"}, stream.Err())
+	})
+
+	t.Run("GIVEN a dropped connection WHEN reconnected THEN values are resumed from the last event", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		prop := observer.NewPropertyOf(0, observer.WithHistoryLimit(100))
+		server := httptest.NewServer(httpx.NewHandler(prop, httpx.WithRetry(10*time.Millisecond)))
+		defer server.Close()
+
+		stream, err := httpx.Dial[int](ctx, server.URL)
+		require.NoError(t, err)
+
+		prop.Update(1)
+		require.Equal(t, 1, stream.WaitNext())
+
+		server.CloseClientConnections()
+		prop.Update(2, 3)
+		prop.End()
+
+		require.Equal(t, 2, stream.WaitNext())
+		require.Equal(t, 3, stream.WaitNext())
+		require.Equal(t, uint64(3), stream.Seq())
+
+		stream.WaitNext()
+		require.True(t, stream.Ended())
+	})
+
+	t.Run("GIVEN a value longer than the line limit WHEN received THEN the local stream fails", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		prop := observer.NewPropertyOf("")
+		server := httptest.NewServer(httpx.NewHandler(prop, httpx.WithRetry(10*time.Millisecond)))
+		defer server.Close()
+
+		stream, err := httpx.Dial[string](ctx, server.URL, httpx.WithMaxLineSize(1024))
+		require.NoError(t, err)
+
+		prop.Update(strings.Repeat("a", 2048))
+
+		stream.WaitNext()
+		require.True(t, stream.Ended())
+		require.True(t, errors.Is(stream.Err(), bufio.ErrTooLong))
+	})
+
+	t.Run("GIVEN a dialed endpoint WHEN context is cancelled THEN the local stream fails with the context error", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		prop := observer.NewPropertyOf(0)
+		server := httptest.NewServer(httpx.NewHandler(prop))
+		defer server.Close()
+
+		stream, err := httpx.Dial[int](ctx, server.URL)
+		require.NoError(t, err)
+
+		cancel()
+
+		stream.WaitNext()
+		require.Equal(t, context.Canceled, stream.Err())
+	})
+
+	t.Run("GIVEN a dialed endpoint WHEN the stream is closed THEN the client disconnects", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		prop := observer.NewPropertyOf(0)
+		server := httptest.NewServer(httpx.NewHandler(prop))
+		defer server.Close()
+
+		stream, err := httpx.Dial[int](ctx, server.URL)
+		require.NoError(t, err)
+		require.Equal(t, 1, prop.Stats().Streams)
+
+		stream.Close()
+		require.Equal(t, observer.ErrClosed, stream.Err())
+		require.Eventually(t, func() bool {
+			return prop.Stats().Streams == 0
+		}, time.Second, time.Millisecond)
+	})
+
+	t.Run("GIVEN an ended remote property WHEN dialed THEN ErrEnded is returned", func(t *testing.T) {
+		prop := observer.NewPropertyOf(0)
+		prop.End()
+
+		server := httptest.NewServer(httpx.NewHandler(prop))
+		defer server.Close()
+
+		_, err := httpx.Dial[int](context.Background(), server.URL)
+		require.Equal(t, httpx.ErrEnded, err)
+	})
+
+	t.Run("GIVEN a dropped connection WHEN the remote property ended meanwhile THEN the local stream ends", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		prop := observer.NewPropertyOf(0)
+		handler := httpx.NewHandler(prop, httpx.WithRetry(10*time.Millisecond))
+
+		// the eof event would be sent before the connection drops otherwise.
+		var down atomic.Bool
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if down.Load() {
+				http.Error(w, "down", http.StatusServiceUnavailable)
+				return
+			}
+
+			handler.ServeHTTP(w, r)
+		}))
+		defer server.Close()
+
+		stream, err := httpx.Dial[int](ctx, server.URL)
+		require.NoError(t, err)
+
+		down.Store(true)
+		server.CloseClientConnections()
+		require.Eventually(t, func() bool {
+			return prop.Stats().Streams == 0
+		}, time.Second, time.Millisecond)
+
+		prop.End()
+		down.Store(false)
+
+		stream.WaitNext()
+		require.True(t, stream.Ended())
+		require.NoError(t, stream.Err())
+	})
+
+	t.Run("GIVEN an endpoint that is not an event stream WHEN dialed THEN an error is returned", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+
+		_, err := httpx.Dial[int](context.Background(), server.URL)
+		require.Error(t, err)
+		require.True(t, strings.Contains(err.Error(), "404"))
+	})
+}
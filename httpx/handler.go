@@ -0,0 +1,118 @@
+// Package httpx exposes properties over HTTP as Server-Sent Events, so
+// browsers and remote processes can observe them, and turns such endpoints
+// back into local streams.
+package httpx
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/botchris/go-observer"
+)
+
+// NewHandler returns an http.Handler streaming the values of the given
+// property as Server-Sent Events. Every value is sent as a "message" event
+// whose id is its sequence number, starting with the current value. Clients
+// resuming with a Last-Event-ID header get the values that follow that
+// sequence number instead, as long as the property still retains it, see
+// observer.PropertyOf.ObserveAt. Once the property ends an EventEOF event is
+// sent, or an EventError event if it failed, and the response is over. If the
+// property has already ended when the request comes in, the response is 204
+// No Content instead, which tells EventSource clients to stop reconnecting.
+func NewHandler[T any](p observer.PropertyOf[T], opts ...Option) http.Handler {
+	return &handler[T]{
+		property: p,
+		opts:     newOptions(opts),
+	}
+}
+
+type handler[T any] struct {
+	property observer.PropertyOf[T]
+	opts     *options
+}
+
+func (h *handler[T]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s, resumed := h.observe(r)
+	defer s.Close()
+
+	if s.Ended() {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	rc := http.NewResponseController(w)
+	retry := h.opts.retry
+
+	if !resumed {
+		if err := h.send(w, s, retry); err != nil {
+			return
+		}
+
+		retry = 0
+	}
+
+	for {
+		if err := rc.Flush(); err != nil || s.Ended() {
+			return
+		}
+
+		if _, err := s.WaitNextContext(r.Context()); err != nil {
+			return
+		}
+
+		if err := h.send(w, s, retry); err != nil {
+			return
+		}
+
+		retry = 0
+	}
+}
+
+// observe returns the stream the response is built from, and whether it was
+// resumed at the value the client already received.
+func (h *handler[T]) observe(r *http.Request) (observer.StreamOf[T], bool) {
+	seq, err := strconv.ParseUint(r.Header.Get("Last-Event-ID"), 10, 64)
+	if err != nil {
+		return h.property.Observe(), false
+	}
+
+	s, err := h.property.ObserveAt(seq)
+	if err != nil {
+		return h.property.Observe(), false
+	}
+
+	return s, true
+}
+
+// send writes the current value of the given stream as an event, or the
+// terminal event once the stream has ended.
+func (h *handler[T]) send(w http.ResponseWriter, s observer.StreamOf[T], retry time.Duration) error {
+	e := event{
+		id:    strconv.FormatUint(s.Seq(), 10),
+		retry: retry,
+	}
+
+	switch {
+	case s.Ended() && s.Err() != nil:
+		e.name, e.data = EventError, []byte(s.Err().Error())
+	case s.Ended():
+		e.name = EventEOF
+	default:
+		data, err := h.opts.encoder(s.Value())
+		if err != nil {
+			e.name, e.data = EventError, []byte(err.Error())
+			writeEvent(w, e)
+
+			return err
+		}
+
+		e.data = data
+	}
+
+	return writeEvent(w, e)
+}
@@ -0,0 +1,171 @@
+package httpx_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/botchris/go-observer"
+	"github.com/botchris/go-observer/httpx"
+	"github.com/stretchr/testify/require"
+)
+
+// get requests the given url and returns a reader over the response body.
+func get(t *testing.T, ctx context.Context, url string, lastEventID string) *bufio.Reader {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	require.NoError(t, err)
+
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	t.Cleanup(func() {
+		resp.Body.Close()
+	})
+
+	return bufio.NewReader(resp.Body)
+}
+
+// readEvent reads the lines of the next event.
+func readEvent(t *testing.T, r *bufio.Reader) string {
+	var lines []string
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+
+		if line == "\n" {
+			return strings.Join(lines, "")
+		}
+
+		lines = append(lines, line)
+	}
+}
+
+type point struct {
+	X, Y int
+}
+
+func TestHandler(t *testing.T) {
+	t.Run("GIVEN a property WHEN observed over HTTP THEN values are sent as events until the end", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		prop := observer.NewPropertyOf(map[string]int{"a": 1})
+		server := httptest.NewServer(httpx.NewHandler(prop, httpx.WithRetry(500*time.Millisecond)))
+		t.Cleanup(server.Close)
+
+		body := get(t, ctx, server.URL, "")
+		require.Equal(t, "retry: 500\nid: 0\ndata: {\"a\":1}\n", readEvent(t, body))
+
+		prop.Update(map[string]int{"a": 2})
+		require.Equal(t, "id: 1\ndata: {\"a\":2}\n", readEvent(t, body))
+
+		prop.End()
+		require.Equal(t, "id: 2\nevent: eof\ndata: \n", readEvent(t, body))
+	})
+
+	t.Run("GIVEN a failed property WHEN observed over HTTP THEN the error is sent as the terminal event", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		prop := observer.NewPropertyOf(1)
+		server := httptest.NewServer(httpx.NewHandler(prop))
+		t.Cleanup(server.Close)
+
+		body := get(t, ctx, server.URL, "")
+		require.Equal(t, "retry: 1000\nid: 0\ndata: 1\n", readEvent(t, body))
+
+		prop.Fail(errors.New("failure"))
+		require.Equal(t, "id: 1\nevent: error\ndata: failure\n", readEvent(t, body))
+	})
+
+	t.Run("GIVEN an ended property WHEN observed over HTTP THEN no content is sent", func(t *testing.T) {
+		prop := observer.NewPropertyOf(0, observer.WithHistoryLimit(10))
+		prop.Update(1)
+		prop.End()
+
+		server := httptest.NewServer(httpx.NewHandler(prop))
+		t.Cleanup(server.Close)
+
+		for _, lastEventID := range []string{"", "2"} {
+			req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+			require.NoError(t, err)
+
+			if lastEventID != "" {
+				req.Header.Set("Last-Event-ID", lastEventID)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+			require.Equal(t, http.StatusNoContent, resp.StatusCode)
+		}
+	})
+
+	t.Run("GIVEN a Last-Event-ID WHEN the property ended after it THEN the terminal event is sent", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		prop := observer.NewPropertyOf(0, observer.WithHistoryLimit(10))
+		prop.Update(1)
+		prop.End()
+
+		server := httptest.NewServer(httpx.NewHandler(prop))
+		t.Cleanup(server.Close)
+
+		body := get(t, ctx, server.URL, "1")
+		require.Equal(t, "retry: 1000\nid: 2\nevent: eof\ndata: \n", readEvent(t, body))
+	})
+
+	t.Run("GIVEN a Last-Event-ID WHEN the sequence is retained THEN values following it are sent", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		prop := observer.NewPropertyOf(0, observer.WithHistoryLimit(10))
+		prop.Update(1, 2, 3)
+
+		server := httptest.NewServer(httpx.NewHandler(prop))
+		t.Cleanup(server.Close)
+
+		body := get(t, ctx, server.URL, "1")
+		require.Equal(t, "retry: 1000\nid: 2\ndata: 2\n", readEvent(t, body))
+		require.Equal(t, "id: 3\ndata: 3\n", readEvent(t, body))
+	})
+
+	t.Run("GIVEN a Last-Event-ID WHEN the sequence is not retained THEN the current value is sent", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		prop := observer.NewPropertyOf(0, observer.WithHistoryLimit(1))
+		prop.Update(1, 2, 3)
+
+		server := httptest.NewServer(httpx.NewHandler(prop))
+		t.Cleanup(server.Close)
+
+		body := get(t, ctx, server.URL, "1")
+		require.Equal(t, "retry: 1000\nid: 3\ndata: 3\n", readEvent(t, body))
+	})
+
+	t.Run("GIVEN a custom encoder WHEN values span multiple lines THEN they are sent as multiple data lines", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		prop := observer.NewPropertyOf("a\nb")
+		server := httptest.NewServer(httpx.NewHandler(prop, httpx.WithEncoder(func(v interface{}) ([]byte, error) {
+			return []byte(v.(string)), nil
+		})))
+		t.Cleanup(server.Close)
+
+		body := get(t, ctx, server.URL, "")
+		require.Equal(t, "retry: 1000\nid: 0\ndata: a\ndata: b\n", readEvent(t, body))
+	})
+}
@@ -0,0 +1,91 @@
+package httpx
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// Option handles configurable handler and client options.
+type Option interface {
+	apply(*options)
+}
+
+type options struct {
+	encoder func(v interface{}) ([]byte, error)
+	decoder func(data []byte, v interface{}) error
+	client  *http.Client
+	retry   time.Duration
+	maxLine int
+}
+
+type funcOption struct {
+	fn func(*options)
+}
+
+func (f *funcOption) apply(o *options) {
+	f.fn(o)
+}
+
+// WithEncoder sets how the handler turns values into the data of events, defaults to json.Marshal.
+func WithEncoder(encoder func(v interface{}) ([]byte, error)) Option {
+	return &funcOption{
+		fn: func(o *options) {
+			o.encoder = encoder
+		},
+	}
+}
+
+// WithDecoder sets how the client turns the data of events back into values, defaults to json.Unmarshal.
+func WithDecoder(decoder func(data []byte, v interface{}) error) Option {
+	return &funcOption{
+		fn: func(o *options) {
+			o.decoder = decoder
+		},
+	}
+}
+
+// WithClient sets the HTTP client used to connect to the endpoint, defaults to http.DefaultClient.
+func WithClient(client *http.Client) Option {
+	return &funcOption{
+		fn: func(o *options) {
+			o.client = client
+		},
+	}
+}
+
+// WithRetry sets how long the client waits before reconnecting after the connection drops, defaults to one second.
+// The handler sends it to clients as the reconnection time of the stream.
+func WithRetry(d time.Duration) Option {
+	return &funcOption{
+		fn: func(o *options) {
+			o.retry = d
+		},
+	}
+}
+
+// WithMaxLineSize sets the size of the longest line of the event stream the client accepts, defaults to 1 MiB. Values
+// whose encoded data is longer make the local stream fail with bufio.ErrTooLong.
+func WithMaxLineSize(n int) Option {
+	return &funcOption{
+		fn: func(o *options) {
+			o.maxLine = n
+		},
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		encoder: json.Marshal,
+		decoder: json.Unmarshal,
+		client:  http.DefaultClient,
+		retry:   time.Second,
+		maxLine: 1 << 20,
+	}
+
+	for _, opt := range opts {
+		opt.apply(o)
+	}
+
+	return o
+}
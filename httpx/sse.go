@@ -0,0 +1,116 @@
+package httpx
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// List of the event types sent besides the values of the property, which are
+// sent as regular "message" events.
+const (
+	// EventEOF is the terminal event sent once the property ends.
+	EventEOF = "eof"
+
+	// EventError is the terminal event sent once the property fails, its data
+	// is the error message.
+	EventError = "error"
+)
+
+// event is a Server-Sent Event, see
+// https://html.spec.whatwg.org/multipage/server-sent-events.html
+type event struct {
+	id    string
+	name  string
+	data  []byte
+	retry time.Duration
+}
+
+// writeEvent writes the given event in the text/event-stream format.
+func writeEvent(w io.Writer, e event) error {
+	var buf bytes.Buffer
+	if e.retry > 0 {
+		fmt.Fprintf(&buf, "retry: %d\n", e.retry.Milliseconds())
+	}
+
+	if e.id != "" {
+		fmt.Fprintf(&buf, "id: %s\n", e.id)
+	}
+
+	if e.name != "" {
+		fmt.Fprintf(&buf, "event: %s\n", e.name)
+	}
+
+	for _, line := range bytes.Split(e.data, []byte("\n")) {
+		fmt.Fprintf(&buf, "data: %s\n", line)
+	}
+
+	buf.WriteByte('\n')
+	_, err := w.Write(buf.Bytes())
+
+	return err
+}
+
+// eventReader parses events in the text/event-stream format.
+type eventReader struct {
+	scanner *bufio.Scanner
+}
+
+// newEventReader creates a reader accepting lines up to max bytes long.
+func newEventReader(r io.Reader, max int) *eventReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, min(max, 64*1024)), max)
+
+	return &eventReader{scanner: scanner}
+}
+
+// next returns the next event, or an error once the stream is over: io.EOF
+// if it ended cleanly, or bufio.ErrTooLong if a line is too long.
+func (r *eventReader) next() (event, error) {
+	var e event
+	var data [][]byte
+	dispatch := false
+
+	for r.scanner.Scan() {
+		line := r.scanner.Text()
+		if line == "" {
+			if dispatch {
+				e.data = bytes.Join(data, []byte("\n"))
+				return e, nil
+			}
+
+			continue
+		}
+
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		dispatch = true
+
+		switch field {
+		case "id":
+			e.id = value
+		case "event":
+			e.name = value
+		case "data":
+			data = append(data, []byte(value))
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil {
+				e.retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return e, err
+	}
+
+	return e, io.EOF
+}
@@ -0,0 +1,136 @@
+package observer
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// NewLazyProperty creates a Property whose value is produced by start, which
+// only runs while the property is being observed. start is called on its own
+// goroutine when the first stream is created, and should keep updating the
+// given property until ctx is done. The context is cancelled once the last
+// stream is closed or garbage collected, or after the grace period set with
+// WithGracePeriod; start is called again when the property is observed anew.
+//
+// The property holds a nil value until the producer updates it, and start is
+// no longer called once the property has ended.
+func NewLazyProperty(start func(ctx context.Context, p Property), opts ...Option) Property {
+	p := NewPropertyOf[interface{}](nil, opts...).(*property[interface{}])
+	l := &lazy{
+		property: p,
+		start:    start,
+	}
+
+	p.onObservers = l.refresh
+
+	return p
+}
+
+type lazy struct {
+	property *property[interface{}]
+	start    func(ctx context.Context, p Property)
+
+	mu      sync.Mutex
+	cancel  context.CancelFunc
+	stopped chan struct{}
+
+	// timer is pending while the grace period runs, grace tells successive
+	// grace periods apart so a stale timer has no effect.
+	timer *time.Timer
+	grace uint64
+}
+
+// refresh starts or stops the producer depending on whether the property is
+// being observed.
+func (l *lazy) refresh() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.property.observed() {
+		if l.timer != nil {
+			l.timer.Stop()
+			l.timer = nil
+		}
+
+		l.run()
+
+		return
+	}
+
+	if l.cancel == nil || l.timer != nil {
+		return
+	}
+
+	if grace := l.property.opts.gracePeriod; grace > 0 {
+		l.grace++
+		generation := l.grace
+		l.timer = time.AfterFunc(grace, func() {
+			l.expire(generation)
+		})
+
+		return
+	}
+
+	l.stop()
+}
+
+// run starts the producer unless it is already running or the property has
+// ended, must be called while holding the lock.
+func (l *lazy) run() {
+	if l.cancel != nil {
+		return
+	}
+
+	select {
+	case <-l.property.Done():
+		return
+	default:
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	previous, stopped := l.stopped, make(chan struct{})
+	l.cancel, l.stopped = cancel, stopped
+
+	// the producer has nothing left to do once the property has ended.
+	go func() {
+		select {
+		case <-l.property.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	go func() {
+		defer close(stopped)
+
+		// never let two producers run at the same time.
+		if previous != nil {
+			<-previous
+		}
+
+		l.start(ctx, l.property)
+	}()
+}
+
+// stop cancels the producer, must be called while holding the lock.
+func (l *lazy) stop() {
+	l.cancel()
+	l.cancel = nil
+}
+
+// expire stops the producer once the given grace period is over, unless the
+// property is being observed again.
+func (l *lazy) expire(generation uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.timer == nil || l.grace != generation {
+		return
+	}
+
+	l.timer = nil
+	if !l.property.observed() {
+		l.stop()
+	}
+}
@@ -0,0 +1,139 @@
+package observer
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// ticker returns a producer that counts up until its context is done,
+// reporting when it starts and stops on the given channels.
+func ticker(started, stopped chan struct{}) func(ctx context.Context, p Property) {
+	return func(ctx context.Context, p Property) {
+		started <- struct{}{}
+		defer func() {
+			stopped <- struct{}{}
+		}()
+
+		for i := 1; ; i++ {
+			p.Update(i)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Millisecond):
+			}
+		}
+	}
+}
+
+func TestLazyPropertyStartsOnObserve(t *testing.T) {
+	started := make(chan struct{}, 10)
+	stopped := make(chan struct{}, 10)
+	prop := NewLazyProperty(ticker(started, stopped))
+	time.Sleep(10 * time.Millisecond)
+	if n := len(started); n != 0 {
+		t.Fatalf("Expecting producer not to be started but got %#v\n", n)
+	}
+	if val := prop.Value(); val != nil {
+		t.Fatalf("Expecting nil but got %#v\n", val)
+	}
+
+	stream := prop.Observe()
+	<-started
+	if val := stream.WaitNext(); val != 1 {
+		t.Fatalf("Expecting 1 but got %#v\n", val)
+	}
+
+	clone := stream.Clone()
+	stream.Close()
+	time.Sleep(10 * time.Millisecond)
+	if n := len(stopped); n != 0 {
+		t.Fatalf("Expecting producer to keep running but got %#v\n", n)
+	}
+
+	clone.Close()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatalf("Expecting producer to be stopped\n")
+	}
+}
+
+func TestLazyPropertyRestarts(t *testing.T) {
+	started := make(chan struct{}, 10)
+	stopped := make(chan struct{}, 10)
+	prop := NewLazyProperty(ticker(started, stopped))
+
+	for i := 0; i < 3; i++ {
+		stream := prop.Observe()
+		<-started
+		stream.WaitNext()
+		stream.Close()
+		<-stopped
+	}
+}
+
+func TestLazyPropertyGracePeriod(t *testing.T) {
+	started := make(chan struct{}, 10)
+	stopped := make(chan struct{}, 10)
+	prop := NewLazyProperty(ticker(started, stopped), WithGracePeriod(100*time.Millisecond))
+
+	stream := prop.Observe()
+	<-started
+	stream.Close()
+	time.Sleep(20 * time.Millisecond)
+
+	stream = prop.Observe()
+	time.Sleep(200 * time.Millisecond)
+	if len(started) != 0 || len(stopped) != 0 {
+		t.Fatalf("Expecting producer to keep running\n")
+	}
+
+	begin := time.Now()
+	stream.Close()
+	<-stopped
+	if elapsed := time.Since(begin); elapsed < 100*time.Millisecond {
+		t.Fatalf("Expecting producer to be stopped after the grace period but got %v\n", elapsed)
+	}
+}
+
+func TestLazyPropertyEnded(t *testing.T) {
+	started := make(chan struct{}, 10)
+	stopped := make(chan struct{}, 10)
+	prop := NewLazyProperty(ticker(started, stopped))
+
+	stream := prop.Observe()
+	<-started
+	prop.End()
+	<-stopped
+
+	prop.Observe()
+	time.Sleep(10 * time.Millisecond)
+	if n := len(started); n != 0 {
+		t.Fatalf("Expecting producer not to be restarted but got %#v\n", n)
+	}
+	stream.Close()
+}
+
+func TestLazyPropertyWaitFor(t *testing.T) {
+	started := make(chan struct{}, 10)
+	stopped := make(chan struct{}, 10)
+	prop := NewLazyProperty(ticker(started, stopped))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	val, err := prop.WaitFor(ctx, func(v interface{}) bool {
+		return v != nil && v.(int) >= 3
+	})
+	if err != nil || val != 3 {
+		t.Fatalf("Expecting 3 but got %#v, %#v\n", val, err)
+	}
+	if n := prop.Stats().Streams; n != 0 {
+		t.Fatalf("Expecting no streams but got %#v\n", n)
+	}
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatalf("Expecting producer to be stopped\n")
+	}
+}
@@ -0,0 +1,103 @@
+package observertest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/botchris/go-observer"
+	"github.com/stretchr/testify/assert"
+)
+
+// AssertEmits asserts that the values following the current one of the given
+// stream are the expected ones, waiting up to Timeout for them. On failure it
+// reports a diff between the expected values and the ones received, and why
+// the stream stopped short if it did. It returns whether the assertion
+// succeeded, and advances the stream through the values it received.
+func AssertEmits[T any](t testing.TB, stream observer.StreamOf[T], expected ...T) bool {
+	t.Helper()
+
+	if len(expected) == 0 {
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+
+	actual := make([]T, 0, len(expected))
+	reason := ""
+	for len(actual) < len(expected) {
+		value, err := stream.WaitNextContext(ctx)
+		if err != nil {
+			reason = fmt.Sprintf("no further values were emitted within %s", Timeout)
+			break
+		}
+
+		if stream.Ended() {
+			reason = ended(stream)
+			break
+		}
+
+		actual = append(actual, value)
+	}
+
+	return assert.Equal(t, expected, actual, reason)
+}
+
+// AssertCompletes asserts that the given stream ends without failing within
+// Timeout, skipping the values it emits before. On failure it reports the
+// values emitted, and the error the stream failed with if any.
+func AssertCompletes[T any](t testing.TB, stream observer.StreamOf[T]) bool {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+
+	emitted := make([]T, 0)
+	for !stream.Ended() {
+		value, err := stream.WaitNextContext(ctx)
+		if err != nil {
+			return assert.Fail(t, fmt.Sprintf("Stream did not complete within %s", Timeout),
+				"emitted: %#v", emitted)
+		}
+
+		if !stream.Ended() {
+			emitted = append(emitted, value)
+		}
+	}
+
+	return assert.NoError(t, stream.Err(), "Stream failed after emitting: %#v", emitted)
+}
+
+// AssertNoEmission asserts that the given stream neither emits a value nor
+// ends for the given duration. On failure it reports the unexpected value, or
+// how the stream ended.
+func AssertNoEmission[T any](t testing.TB, stream observer.StreamOf[T], d time.Duration) bool {
+	t.Helper()
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-stream.Changes():
+	}
+
+	value := stream.Next()
+	if stream.Ended() {
+		return assert.Fail(t, fmt.Sprintf("Unexpected end within %s", d), ended(stream))
+	}
+
+	return assert.Fail(t, fmt.Sprintf("Unexpected emission within %s", d), "emitted: %#v", value)
+}
+
+// ended describes how the given ended stream ended.
+func ended[T any](stream observer.StreamOf[T]) string {
+	if err := stream.Err(); err != nil {
+		return fmt.Sprintf("the stream failed: %v", err)
+	}
+
+	return "the stream ended"
+}
@@ -0,0 +1,142 @@
+package observertest_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/botchris/go-observer"
+	"github.com/botchris/go-observer/observertest"
+	"github.com/stretchr/testify/require"
+)
+
+// recorderT records the failures reported by the assertions under test.
+type recorderT struct {
+	testing.TB
+	failures []string
+}
+
+func (t *recorderT) Helper() {}
+
+func (t *recorderT) Errorf(format string, args ...interface{}) {
+	t.failures = append(t.failures, fmt.Sprintf(format, args...))
+}
+
+func withTimeout(t *testing.T, d time.Duration) {
+	timeout := observertest.Timeout
+	observertest.Timeout = d
+	t.Cleanup(func() {
+		observertest.Timeout = timeout
+	})
+}
+
+func TestAssertEmits(t *testing.T) {
+	t.Run("GIVEN a stream WHEN it emits the expected values THEN the assertion succeeds", func(t *testing.T) {
+		prop := observer.NewPropertyOf(0)
+		stream := prop.Observe()
+
+		go prop.Update(1, 2, 3)
+
+		require.True(t, observertest.AssertEmits(t, stream, 1, 2))
+		require.Equal(t, 2, stream.Value())
+	})
+
+	t.Run("GIVEN a stream WHEN it emits other values THEN a diff is reported", func(t *testing.T) {
+		prop := observer.NewPropertyOf(0)
+		stream := prop.Observe()
+		prop.Update(1, 5)
+
+		rt := &recorderT{TB: t}
+		require.False(t, observertest.AssertEmits(rt, stream, 1, 2))
+		require.Len(t, rt.failures, 1)
+		require.Contains(t, rt.failures[0], "Diff:")
+	})
+
+	t.Run("GIVEN a stream WHEN it emits too few values THEN the timeout is reported", func(t *testing.T) {
+		withTimeout(t, 10*time.Millisecond)
+
+		prop := observer.NewPropertyOf(0)
+		prop.Update(1)
+
+		rt := &recorderT{TB: t}
+		require.False(t, observertest.AssertEmits(rt, prop.Observe(), 1, 2))
+		require.Len(t, rt.failures, 1)
+		require.Contains(t, rt.failures[0], "no further values were emitted within 10ms")
+	})
+
+	t.Run("GIVEN a stream WHEN it fails early THEN the failure is reported", func(t *testing.T) {
+		prop := observer.NewPropertyOf(0)
+		stream := prop.Observe()
+		prop.Update(1)
+		prop.Fail(errors.New("boom"))
+
+		rt := &recorderT{TB: t}
+		require.False(t, observertest.AssertEmits(rt, stream, 1, 2))
+		require.Contains(t, rt.failures[0], "the stream failed: boom")
+	})
+}
+
+func TestAssertCompletes(t *testing.T) {
+	t.Run("GIVEN a stream WHEN its property ends THEN the assertion succeeds", func(t *testing.T) {
+		prop := observer.NewProperty(0)
+		stream := prop.Observe()
+
+		go func() {
+			prop.Update(1)
+			prop.End()
+		}()
+
+		require.True(t, observertest.AssertCompletes(t, stream))
+	})
+
+	t.Run("GIVEN a stream WHEN its property fails THEN the error is reported", func(t *testing.T) {
+		prop := observer.NewProperty(0)
+		stream := prop.Observe()
+		prop.Update(1)
+		prop.Fail(errors.New("boom"))
+
+		rt := &recorderT{TB: t}
+		require.False(t, observertest.AssertCompletes(rt, stream))
+		require.Len(t, rt.failures, 1)
+		require.Contains(t, rt.failures[0], "boom")
+		require.Contains(t, rt.failures[0], "Stream failed after emitting: []interface {}{1}")
+	})
+
+	t.Run("GIVEN a stream WHEN its property doesn't end THEN the timeout is reported", func(t *testing.T) {
+		withTimeout(t, 10*time.Millisecond)
+
+		rt := &recorderT{TB: t}
+		require.False(t, observertest.AssertCompletes(rt, observer.NewProperty(0).Observe()))
+		require.Contains(t, rt.failures[0], "Stream did not complete within 10ms")
+	})
+}
+
+func TestAssertNoEmission(t *testing.T) {
+	t.Run("GIVEN a stream WHEN nothing is emitted THEN the assertion succeeds", func(t *testing.T) {
+		prop := observer.NewPropertyOf(0)
+		require.True(t, observertest.AssertNoEmission(t, prop.Observe(), 10*time.Millisecond))
+	})
+
+	t.Run("GIVEN a stream WHEN a value is emitted THEN the value is reported", func(t *testing.T) {
+		prop := observer.NewPropertyOf(0)
+		stream := prop.Observe()
+		prop.Update(42)
+
+		rt := &recorderT{TB: t}
+		require.False(t, observertest.AssertNoEmission(rt, stream, time.Second))
+		require.Len(t, rt.failures, 1)
+		require.Contains(t, rt.failures[0], "Unexpected emission within 1s")
+		require.Contains(t, rt.failures[0], "emitted: 42")
+	})
+
+	t.Run("GIVEN a stream WHEN it ends THEN the end is reported", func(t *testing.T) {
+		prop := observer.NewPropertyOf(0)
+		stream := prop.Observe()
+		prop.End()
+
+		rt := &recorderT{TB: t}
+		require.False(t, observertest.AssertNoEmission(rt, stream, time.Second))
+		require.Contains(t, rt.failures[0], "Unexpected end within 1s")
+	})
+}
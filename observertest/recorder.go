@@ -0,0 +1,130 @@
+// Package observertest provides utilities to test code built on top of
+// properties, streams and operables: a Recorder that collects the values of a
+// stream in the background, and assertions that wait for streams to emit or
+// complete without hand-written sleeps.
+package observertest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/botchris/go-observer"
+)
+
+// Timeout is how long Recorder.Wait and the assertions of this package wait
+// for a stream before giving up.
+var Timeout = time.Second
+
+// Recorder collects the values of a stream on its own goroutine, so tests can
+// keep publishing values and check what was observed afterwards. It is
+// goroutine safe.
+type Recorder[T any] struct {
+	cancel  context.CancelFunc
+	stopped chan struct{}
+
+	mu      sync.Mutex
+	values  []T
+	ended   bool
+	err     error
+	changed chan struct{}
+}
+
+// Record starts recording the values that follow the current one of the given
+// stream, until it ends or the recorder is stopped. The recorder takes over
+// the stream, which must not be used elsewhere afterwards.
+func Record[T any](stream observer.StreamOf[T]) *Recorder[T] {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &Recorder[T]{
+		cancel:  cancel,
+		stopped: make(chan struct{}),
+		changed: make(chan struct{}),
+	}
+
+	go r.run(ctx, stream)
+
+	return r
+}
+
+// run records the values of the given stream until it ends or ctx is done.
+func (r *Recorder[T]) run(ctx context.Context, stream observer.StreamOf[T]) {
+	defer close(r.stopped)
+	defer stream.Close()
+
+	for {
+		value, err := stream.WaitNextContext(ctx)
+		if err != nil {
+			return
+		}
+
+		r.mu.Lock()
+		if stream.Ended() {
+			r.ended, r.err = true, stream.Err()
+		} else {
+			r.values = append(r.values, value)
+		}
+
+		close(r.changed)
+		r.changed = make(chan struct{})
+		r.mu.Unlock()
+
+		if stream.Ended() {
+			return
+		}
+	}
+}
+
+// Values returns the values recorded so far.
+func (r *Recorder[T]) Values() []T {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]T(nil), r.values...)
+}
+
+// Ended reports whether the recorded stream has ended.
+func (r *Recorder[T]) Ended() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.ended
+}
+
+// Err returns the error the recorded stream failed with, if it has ended.
+func (r *Recorder[T]) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.err
+}
+
+// Wait blocks until at least n values have been recorded, the stream ends, or
+// Timeout elapses. It returns the values recorded so far, and whether there
+// are at least n of them.
+func (r *Recorder[T]) Wait(n int) ([]T, bool) {
+	timeout := time.NewTimer(Timeout)
+	defer timeout.Stop()
+
+	for {
+		r.mu.Lock()
+		values, ended, changed := append([]T(nil), r.values...), r.ended, r.changed
+		r.mu.Unlock()
+
+		if len(values) >= n || ended {
+			return values, len(values) >= n
+		}
+
+		select {
+		case <-changed:
+		case <-timeout.C:
+			return values, false
+		}
+	}
+}
+
+// Stop stops recording and closes the stream, the values recorded so far are
+// kept. It returns once the recording goroutine has exited.
+func (r *Recorder[T]) Stop() {
+	r.cancel()
+	<-r.stopped
+}
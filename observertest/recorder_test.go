@@ -0,0 +1,64 @@
+package observertest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/botchris/go-observer"
+	"github.com/botchris/go-observer/observertest"
+	"github.com/botchris/go-observer/rx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	t.Run("GIVEN a recorder WHEN the property is updated THEN values are recorded", func(t *testing.T) {
+		prop := observer.NewPropertyOf(0)
+		recorder := observertest.Record(prop.Observe())
+		defer recorder.Stop()
+
+		prop.Update(1, 2, 3)
+
+		values, ok := recorder.Wait(3)
+		require.True(t, ok)
+		require.Equal(t, []int{1, 2, 3}, values)
+		require.False(t, recorder.Ended())
+	})
+
+	t.Run("GIVEN a recorder WHEN the property fails THEN the failure is recorded", func(t *testing.T) {
+		prop := observer.NewProperty(nil)
+		recorder := observertest.Record(prop.Observe())
+
+		failure := errors.New("failure")
+		prop.Update(1)
+		prop.Fail(failure)
+
+		values, ok := recorder.Wait(2)
+		require.False(t, ok)
+		require.Equal(t, []interface{}{1}, values)
+		require.True(t, recorder.Ended())
+		require.Equal(t, failure, recorder.Err())
+
+		recorder.Stop()
+	})
+
+	t.Run("GIVEN a recorder of an operable WHEN stopped THEN no more values are recorded", func(t *testing.T) {
+		prop := observer.NewProperty(0)
+		operable := rx.MakeOperable(context.Background(), prop.Observe()).
+			Map(func(_ context.Context, v interface{}) interface{} {
+				return v.(int) * 10
+			})
+
+		recorder := observertest.Record(operable)
+		prop.Update(1)
+
+		values, ok := recorder.Wait(1)
+		require.True(t, ok)
+		require.Equal(t, []interface{}{10}, values)
+
+		recorder.Stop()
+		prop.Update(2)
+
+		require.Equal(t, []interface{}{10}, recorder.Values())
+	})
+}
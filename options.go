@@ -0,0 +1,106 @@
+package observer
+
+import "time"
+
+// Option handles configurable property options.
+type Option interface {
+	apply(*options)
+}
+
+type options struct {
+	historyLimit    int
+	historyDuration time.Duration
+	replay          bool
+	endOnAnySource  bool
+	gracePeriod     time.Duration
+	startSeq        uint64
+}
+
+type funcOption struct {
+	fn func(*options)
+}
+
+func (f *funcOption) apply(o *options) {
+	f.fn(o)
+}
+
+// WithHistoryLimit caps the number of values a property retains in memory to
+// the given limit, by default there is no limit and values are retained for
+// as long as the slowest stream needs them. Streams that fall more than limit
+// values behind skip the discarded values and continue from the oldest
+// retained one, see StreamOf.Lagged.
+func WithHistoryLimit(limit int) Option {
+	return &funcOption{
+		fn: func(o *options) {
+			o.historyLimit = limit
+		},
+	}
+}
+
+// WithHistoryDuration makes a property retain in memory the values it was
+// updated to during the given duration, so they can be replayed to new streams
+// with ObserveFrom, ObserveSince or WithReplay. The current value is always
+// retained. When combined with WithHistoryLimit, values are discarded as soon
+// as any of both limits is exceeded.
+func WithHistoryDuration(d time.Duration) Option {
+	return &funcOption{
+		fn: func(o *options) {
+			o.historyDuration = d
+		},
+	}
+}
+
+// WithReplay makes Observe start new streams at the oldest value retained in
+// the history of the property instead of its current value, so late observers
+// don't miss the values that came before. It is meant to be used along with
+// WithHistoryLimit or WithHistoryDuration, otherwise only the current value is
+// retained.
+func WithReplay() Option {
+	return &funcOption{
+		fn: func(o *options) {
+			o.replay = true
+		},
+	}
+}
+
+// WithEndOnAnySource makes a derived property end as soon as any of its
+// sources ends, instead of waiting for all of them. See DeriveWith.
+func WithEndOnAnySource() Option {
+	return &funcOption{
+		fn: func(o *options) {
+			o.endOnAnySource = true
+		},
+	}
+}
+
+// WithGracePeriod makes a lazy property wait for the given duration after its
+// last stream is closed before stopping its producer, so observers that come
+// and go in quick succession don't restart it every time. See NewLazyProperty.
+func WithGracePeriod(d time.Duration) Option {
+	return &funcOption{
+		fn: func(o *options) {
+			o.gracePeriod = d
+		},
+	}
+}
+
+// WithStartSeq numbers the initial value of a property with the given sequence
+// number instead of 0, so sequence numbers carry on from where a previous run
+// left off, e.g. when restoring a property from persisted state. See
+// StreamOf.Seq.
+func WithStartSeq(seq uint64) Option {
+	return &funcOption{
+		fn: func(o *options) {
+			o.startSeq = seq
+		},
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt.apply(o)
+	}
+
+	return o
+}
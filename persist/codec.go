@@ -0,0 +1,104 @@
+package persist
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// Encoder writes values to an underlying writer, one after another.
+type Encoder interface {
+	Encode(v interface{}) error
+}
+
+// Decoder reads the values written by an Encoder, one after another.
+type Decoder interface {
+	Decode(v interface{}) error
+}
+
+// Codec defines how values are serialized to and from durable storage.
+type Codec interface {
+	NewEncoder(w io.Writer) Encoder
+	NewDecoder(r io.Reader) Decoder
+}
+
+// List of built-in codecs
+var (
+	// JSON encodes values as a stream of JSON documents, see encoding/json.
+	JSON Codec = jsonCodec{}
+
+	// Gob encodes values with encoding/gob. Each value is written as a
+	// self-contained gob message prefixed by its length, so the values
+	// written by successive encoders, e.g. a journal reopened in append mode
+	// after a restart, can be read back by a single decoder. The concrete
+	// types held by untyped properties must be registered with gob.Register.
+	Gob Codec = gobCodec{}
+)
+
+type jsonCodec struct{}
+
+func (jsonCodec) NewEncoder(w io.Writer) Encoder {
+	return json.NewEncoder(w)
+}
+
+func (jsonCodec) NewDecoder(r io.Reader) Decoder {
+	return json.NewDecoder(r)
+}
+
+type gobCodec struct{}
+
+func (gobCodec) NewEncoder(w io.Writer) Encoder {
+	return &gobEncoder{w: w}
+}
+
+func (gobCodec) NewDecoder(r io.Reader) Decoder {
+	return &gobDecoder{r: r}
+}
+
+// gobEncoder writes every value as its own length-prefixed gob message, type
+// definitions included.
+type gobEncoder struct {
+	w io.Writer
+}
+
+func (e *gobEncoder) Encode(v interface{}) error {
+	buf := bytes.NewBuffer(make([]byte, 4))
+	if err := gob.NewEncoder(buf).Encode(v); err != nil {
+		return err
+	}
+
+	b := buf.Bytes()
+	binary.BigEndian.PutUint32(b[0:4], uint32(len(b)-4))
+
+	// a single write, so a message is either fully appended or cut short.
+	_, err := e.w.Write(b)
+
+	return err
+}
+
+// gobDecoder reads the messages written by gobEncoder. A message cut short
+// is reported as io.ErrUnexpectedEOF.
+type gobDecoder struct {
+	r io.Reader
+}
+
+func (d *gobDecoder) Decode(v interface{}) error {
+	var header [4]byte
+	if _, err := io.ReadFull(d.r, header[:]); err != nil {
+		return err
+	}
+
+	payload := make([]byte, binary.BigEndian.Uint32(header[:]))
+	if _, err := io.ReadFull(d.r, payload); err != nil {
+		if errors.Is(err, io.EOF) {
+			return io.ErrUnexpectedEOF
+		}
+
+		return err
+	}
+
+	return gob.NewDecoder(bytes.NewReader(payload)).Decode(v)
+}
@@ -0,0 +1,79 @@
+package persist
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/botchris/go-observer"
+)
+
+// Journal appends the values a property is updated to to an underlying
+// writer, so its retained history can be replayed after a restart, see Replay.
+// The writer is expected to be opened in append mode. It is goroutine safe.
+type Journal[T any] struct {
+	mu  sync.Mutex
+	enc Encoder
+}
+
+// NewJournal creates a journal writing to w with the given codec.
+func NewJournal[T any](w io.Writer, codec Codec) *Journal[T] {
+	return &Journal[T]{
+		enc: codec.NewEncoder(w),
+	}
+}
+
+// Append writes the given value along with its sequence number.
+func (j *Journal[T]) Append(seq uint64, value T) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.enc.Encode(Record[T]{Seq: seq, Value: value})
+}
+
+// Record appends every value following the current one of the given stream,
+// until the stream ends or ctx is done. It returns nil once the property ends,
+// the error it failed with, the context error, or the first error writing to
+// the journal.
+func (j *Journal[T]) Record(ctx context.Context, s observer.StreamOf[T]) error {
+	for value := range s.Values(ctx) {
+		if err := j.Append(s.Seq(), value); err != nil {
+			return err
+		}
+	}
+
+	if err := ctx.Err(); err != nil && !s.Ended() {
+		return err
+	}
+
+	return s.Err()
+}
+
+// Replay updates the given property with the values read from r, as written by
+// a Journal, skipping those at or before its current sequence number, e.g. the
+// ones already included in the snapshot it was restored from. A journal whose
+// last record was cut short by a crash is replayed up to that record.
+func Replay[T any](r io.Reader, codec Codec, p observer.PropertyOf[T]) error {
+	s := p.ObserveFrom(0)
+	seq := s.Seq()
+	s.Close()
+
+	dec := codec.NewDecoder(r)
+	for {
+		var record Record[T]
+		err := dec.Decode(&record)
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if record.Seq > seq {
+			p.Update(record.Value)
+			seq = record.Seq
+		}
+	}
+}
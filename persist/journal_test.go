@@ -0,0 +1,120 @@
+package persist_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/botchris/go-observer"
+	"github.com/botchris/go-observer/persist"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJournal(t *testing.T) {
+	for name, codec := range codecs {
+		t.Run("GIVEN a snapshot and a journal encoded with "+name+" WHEN replayed THEN history is restored", func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			prop := observer.NewPropertyOf(point{})
+			journal := &bytes.Buffer{}
+			recorded := make(chan error)
+			stream := prop.Observe()
+			go func() {
+				recorded <- persist.NewJournal[point](journal, codec).Record(ctx, stream)
+			}()
+
+			prop.Update(point{1, 1})
+
+			snapshot := &bytes.Buffer{}
+			require.NoError(t, persist.Snapshot(snapshot, prop, codec))
+
+			prop.Update(point{2, 2}, point{3, 3})
+			prop.End()
+			require.NoError(t, <-recorded)
+
+			restored, err := persist.Restore[point](snapshot, codec, observer.WithHistoryLimit(10), observer.WithReplay())
+			require.NoError(t, err)
+			require.NoError(t, persist.Replay(journal, codec, restored))
+
+			replay := restored.Observe()
+			require.Equal(t, point{1, 1}, replay.Value())
+			require.Equal(t, uint64(1), replay.Seq())
+			require.Equal(t, point{2, 2}, replay.Next())
+			require.Equal(t, point{3, 3}, replay.Next())
+			require.Equal(t, uint64(3), replay.Seq())
+			require.False(t, replay.HasNext())
+		})
+
+		t.Run("GIVEN a journal encoded with "+name+" appended to by two sessions WHEN replayed THEN every record is replayed", func(t *testing.T) {
+			journal := &bytes.Buffer{}
+			require.NoError(t, persist.NewJournal[point](journal, codec).Append(1, point{1, 1}))
+			require.NoError(t, persist.NewJournal[point](journal, codec).Append(2, point{2, 2}))
+
+			prop := observer.NewPropertyOf(point{})
+			require.NoError(t, persist.Replay(journal, codec, prop))
+			require.Equal(t, point{2, 2}, prop.Value())
+		})
+
+		t.Run("GIVEN a journal encoded with "+name+" cut short WHEN replayed THEN complete records are replayed", func(t *testing.T) {
+			journal := &bytes.Buffer{}
+			j := persist.NewJournal[point](journal, codec)
+			require.NoError(t, j.Append(1, point{1, 1}))
+			require.NoError(t, j.Append(2, point{2, 2}))
+			journal.Truncate(journal.Len() - 4)
+
+			prop := observer.NewPropertyOf(point{})
+			require.NoError(t, persist.Replay(journal, codec, prop))
+			require.Equal(t, point{1, 1}, prop.Value())
+		})
+	}
+
+	t.Run("GIVEN a journal cut short WHEN replayed THEN complete records are replayed", func(t *testing.T) {
+		journal := &bytes.Buffer{}
+		j := persist.NewJournal[int](journal, persist.JSON)
+		require.NoError(t, j.Append(1, 10))
+		require.NoError(t, j.Append(2, 20))
+		journal.Truncate(journal.Len() - 4)
+
+		prop := observer.NewPropertyOf(0)
+		require.NoError(t, persist.Replay(journal, persist.JSON, prop))
+		require.Equal(t, 10, prop.Value())
+	})
+
+	t.Run("GIVEN a property with replay WHEN replayed THEN records it already holds are skipped", func(t *testing.T) {
+		journal := &bytes.Buffer{}
+		j := persist.NewJournal[int](journal, persist.JSON)
+		require.NoError(t, j.Append(1, 10))
+		require.NoError(t, j.Append(2, 20))
+
+		prop := observer.NewPropertyOf(0, observer.WithHistoryLimit(10), observer.WithReplay())
+		prop.Update(10)
+		require.NoError(t, persist.Replay(journal, persist.JSON, prop))
+
+		stream := prop.Observe()
+		require.Equal(t, []int{10, 20}, stream.Drain(0))
+		require.Equal(t, uint64(2), stream.Seq())
+	})
+
+	t.Run("GIVEN a recorded property WHEN it fails THEN the error is returned", func(t *testing.T) {
+		failure := errors.New("failure")
+		prop := observer.NewPropertyOf(0)
+		stream := prop.Observe()
+		prop.Update(1)
+		prop.Fail(failure)
+
+		err := persist.NewJournal[int](&bytes.Buffer{}, persist.JSON).Record(context.Background(), stream)
+		require.Equal(t, failure, err)
+	})
+
+	t.Run("GIVEN a recorded property WHEN context is cancelled THEN the context error is returned", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		prop := observer.NewPropertyOf(0)
+		err := persist.NewJournal[int](&bytes.Buffer{}, persist.JSON).Record(ctx, prop.Observe())
+		require.Equal(t, context.Canceled, err)
+	})
+}
@@ -0,0 +1,87 @@
+// Package persist saves the state of properties to durable storage, so they
+// can be restored when the process restarts instead of starting over from
+// their initial value.
+package persist
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/botchris/go-observer"
+)
+
+// Record is the persisted form of a property value, along with its sequence
+// number so the restored property carries on with the same numbering.
+type Record[T any] struct {
+	Seq   uint64
+	Value T
+}
+
+// Snapshot writes the current value of the given property to w.
+func Snapshot[T any](w io.Writer, p observer.PropertyOf[T], codec Codec) error {
+	s := p.ObserveFrom(0)
+	defer s.Close()
+
+	return codec.NewEncoder(w).Encode(Record[T]{Seq: s.Seq(), Value: s.Value()})
+}
+
+// Restore creates a property holding the value read from r, as written by
+// Snapshot. The property is created with the given options.
+func Restore[T any](r io.Reader, codec Codec, opts ...observer.Option) (observer.PropertyOf[T], error) {
+	var record Record[T]
+	if err := codec.NewDecoder(r).Decode(&record); err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, observer.WithStartSeq(record.Seq))
+
+	return observer.NewPropertyOf(record.Value, opts...), nil
+}
+
+// SnapshotFile writes the current value of the given property to the named
+// file. The snapshot is written to a temporary file first, which then replaces
+// the named one, so a crash never leaves a partially written snapshot behind.
+func SnapshotFile[T any](name string, p observer.PropertyOf[T], codec Codec) error {
+	f, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(f.Name())
+
+	if err := Snapshot(f, p, codec); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), name)
+}
+
+// RestoreFile creates a property holding the value read from the named file,
+// as written by SnapshotFile. If the file does not exist the property holds the
+// given initial value instead, as if it was created with NewPropertyOf.
+func RestoreFile[T any](name string, value T, codec Codec, opts ...observer.Option) (observer.PropertyOf[T], error) {
+	f, err := os.Open(name)
+	if errors.Is(err, os.ErrNotExist) {
+		return observer.NewPropertyOf(value, opts...), nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	return Restore[T](f, codec, opts...)
+}
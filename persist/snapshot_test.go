@@ -0,0 +1,92 @@
+package persist_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/botchris/go-observer"
+	"github.com/botchris/go-observer/persist"
+	"github.com/stretchr/testify/require"
+)
+
+type point struct {
+	X, Y int
+}
+
+var codecs = map[string]persist.Codec{
+	"JSON": persist.JSON,
+	"Gob":  persist.Gob,
+}
+
+func TestSnapshot(t *testing.T) {
+	for name, codec := range codecs {
+		t.Run("GIVEN a snapshot encoded with "+name+" WHEN restored THEN value and sequence number are preserved", func(t *testing.T) {
+			prop := observer.NewPropertyOf(point{})
+			prop.Update(point{1, 2}, point{3, 4})
+
+			buf := &bytes.Buffer{}
+			require.NoError(t, persist.Snapshot(buf, prop, codec))
+
+			restored, err := persist.Restore[point](buf, codec)
+			require.NoError(t, err)
+
+			stream := restored.Observe()
+			require.Equal(t, point{3, 4}, stream.Value())
+			require.Equal(t, uint64(2), stream.Seq())
+
+			restored.Update(point{5, 6})
+			require.Equal(t, point{5, 6}, stream.WaitNext())
+			require.Equal(t, uint64(3), stream.Seq())
+		})
+	}
+
+	t.Run("GIVEN a property with replay WHEN snapshotted THEN its current value is written", func(t *testing.T) {
+		prop := observer.NewPropertyOf(1, observer.WithHistoryLimit(10), observer.WithReplay())
+		prop.Update(2, 3)
+
+		buf := &bytes.Buffer{}
+		require.NoError(t, persist.Snapshot(buf, prop, persist.JSON))
+
+		restored, err := persist.Restore[int](buf, persist.JSON)
+		require.NoError(t, err)
+		require.Equal(t, 3, restored.Value())
+		require.Equal(t, uint64(2), restored.Observe().Seq())
+	})
+
+	t.Run("GIVEN an invalid snapshot WHEN restored THEN an error is returned", func(t *testing.T) {
+		_, err := persist.Restore[point](bytes.NewBufferString("{"), persist.JSON)
+		require.Error(t, err)
+	})
+}
+
+func TestSnapshotFile(t *testing.T) {
+	t.Run("GIVEN no snapshot file WHEN restored THEN the initial value is used", func(t *testing.T) {
+		name := filepath.Join(t.TempDir(), "state")
+
+		prop, err := persist.RestoreFile(name, 10, persist.JSON)
+		require.NoError(t, err)
+		require.Equal(t, 10, prop.Value())
+	})
+
+	t.Run("GIVEN a snapshot file WHEN restored THEN the snapshot value is used", func(t *testing.T) {
+		dir := t.TempDir()
+		name := filepath.Join(dir, "state")
+
+		prop := observer.NewPropertyOf(10)
+		prop.Update(20)
+		require.NoError(t, persist.SnapshotFile(name, prop, persist.Gob))
+
+		prop.Update(30)
+		require.NoError(t, persist.SnapshotFile(name, prop, persist.Gob))
+
+		restored, err := persist.RestoreFile(name, 10, persist.Gob)
+		require.NoError(t, err)
+		require.Equal(t, 30, restored.Value())
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+	})
+}
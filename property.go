@@ -1,83 +1,429 @@
 package observer
 
 import (
-	"io"
+	"context"
+	"errors"
 	"sync"
+	"time"
 )
 
-// Property is an object that is continuously updated by one or more
-// publishers. It is completely goroutine safe: you can use Property
-// concurrently from multiple goroutines.
-type Property interface {
+var (
+	// ErrSeqNotRetained is returned when resuming at a sequence number that has
+	// already been discarded from the history of a property.
+	ErrSeqNotRetained = errors.New("observer: sequence number is no longer retained")
+
+	// ErrSeqOutOfRange is returned when resuming at a sequence number that a
+	// property has not reached yet.
+	ErrSeqOutOfRange = errors.New("observer: sequence number is out of range")
+)
+
+// PropertyOf is an object that is continuously updated by one or more
+// publishers with values of type T. It is completely goroutine safe: you can
+// use PropertyOf concurrently from multiple goroutines.
+type PropertyOf[T any] interface {
 	// Value returns the current value for this property.
-	Value() interface{}
+	Value() T
 
 	// Update sets new values for this property.
 	// Updating with `io.EOF` will mark this property as "ended",
 	// once ended further calls to Update will no-op
-	Update(value ...interface{})
+	Update(value ...T)
+
+	// UpdateWithMeta is like Update but records who made the update, and any
+	// headers such as trace IDs, along with the value. Streams report them
+	// with StreamOf.Meta. The Time and Seq fields of meta are ignored, and
+	// its headers must not be modified afterwards.
+	UpdateWithMeta(value T, meta Meta)
+
+	// UpdateFunc atomically sets the value of this property to the result of
+	// calling fn with its current value, so concurrent publishers don't
+	// overwrite each other. It returns the resulting value of the property.
+	// fn is called while holding the property lock, so it must not call any
+	// other method of this property. It no-ops once the property has ended.
+	UpdateFunc(fn func(old T) T) T
+
+	// CompareAndSwap atomically sets the value of this property to new if its
+	// current value is old, and reports whether it did so. Values are compared
+	// as interfaces, so it panics if they are not comparable.
+	CompareAndSwap(old, new T) bool
 
 	// Observe returns a newly created Stream for this property.
-	Observe() Stream
+	Observe() StreamOf[T]
+
+	// ObserveFrom returns a newly created Stream that starts n values before
+	// the current one, so the values that came before can be replayed. It
+	// starts at the oldest retained value when the history of this property
+	// holds less than n previous values, see WithHistoryLimit and
+	// WithHistoryDuration.
+	ObserveFrom(n int) StreamOf[T]
 
-	// End emits a io.EOF message, shortcut for `Property.Update(io.EOF)`
+	// ObserveSince returns a newly created Stream that starts at the oldest
+	// retained value this property was updated to at or after t. It starts at
+	// the current value if there is no such value.
+	ObserveSince(t time.Time) StreamOf[T]
+
+	// ObserveAt returns a newly created Stream that starts at the value with
+	// the given sequence number, see StreamOf.Seq. Consumers can record the
+	// sequence number of the last value they processed, and resume from there
+	// as long as it is retained in the history of this property. It returns
+	// ErrSeqNotRetained if the value has been discarded, or ErrSeqOutOfRange
+	// if the property has not reached the given sequence number yet.
+	ObserveAt(seq uint64) (StreamOf[T], error)
+
+	// WaitFor blocks until the value of this property satisfies the predicate,
+	// the current value is checked first. See StreamOf.WaitUntil for the
+	// returned errors.
+	WaitFor(ctx context.Context, predicate func(T) bool) (T, error)
+
+	// End marks this property as "ended". Untyped properties emit a io.EOF
+	// message, so this is a shortcut for `Property.Update(io.EOF)`
 	End()
 
+	// Fail marks this property as "ended" because of the given error. Streams
+	// see the end of the property as they do with End, and their Err method
+	// reports err. Failing with a nil error is the same as calling End.
+	Fail(err error)
+
 	// Done returns a channel that is closed when property reaches a EOF
 	Done() <-chan struct{}
+
+	// Err returns the error this property failed with, or nil if it has not
+	// failed.
+	Err() error
+
+	// Stats returns a snapshot of how this property is being observed: its
+	// active streams, how far behind each of them is, and the oldest value
+	// retained in memory. Streams stop being accounted for once they are
+	// closed or garbage collected.
+	Stats() Stats
 }
 
+// Property is an object that is continuously updated by one or more
+// publishers. It is completely goroutine safe: you can use Property
+// concurrently from multiple goroutines.
+type Property = PropertyOf[interface{}]
+
 // NewProperty creates a new Property with the initial value value.
 // It returns the created Property.
-func NewProperty(value interface{}) Property {
-	return &property{
+func NewProperty(value interface{}, opts ...Option) Property {
+	return NewPropertyOf[interface{}](value, opts...)
+}
+
+// NewPropertyOf creates a new PropertyOf[T] with the initial value value.
+// It returns the created PropertyOf[T].
+func NewPropertyOf[T any](value T, opts ...Option) PropertyOf[T] {
+	p := &property[T]{
+		id:    propertyIDs.Add(1),
+		opts:  newOptions(opts),
 		state: newState(value),
 		done:  make(chan struct{}),
 	}
+
+	p.state.seq = p.opts.startSeq
+	if p.opts.historyLimit > 0 || p.opts.historyDuration > 0 {
+		p.oldest = p.state
+	}
+
+	return p
 }
 
-type property struct {
+type property[T any] struct {
 	sync.RWMutex
+	id    uint64 // orders the locks taken by transactions, see Transaction
+	opts  *options
 	ended bool
+	err   error
 	done  chan struct{}
-	state *state
+	state *state[T]
+
+	// oldest is the oldest state retained in memory, only tracked when
+	// history is limited by size or duration. trimmed is the last state discarded from history,
+	// it is kept until the next one is discarded so its link can be cut.
+	oldest  *state[T]
+	trimmed *state[T]
+
+	observers observers
+
+	// onRead and onObservers are optional hooks used by the constructs built
+	// on top of a property: they are called before its value is read and
+	// after a stream is registered or unregistered, respectively.
+	onRead      func()
+	onObservers func()
 }
 
-func (p *property) Value() interface{} {
+func (p *property[T]) Value() T {
+	p.read()
+
 	p.RLock()
 	defer p.RUnlock()
 
 	return p.state.value
 }
 
-func (p *property) Update(values ...interface{}) {
+func (p *property[T]) Update(values ...T) {
 	p.Lock()
 	defer p.Unlock()
 
+	for _, value := range values {
+		p.update(value)
+	}
+}
+
+func (p *property[T]) UpdateWithMeta(value T, meta Meta) {
+	p.Lock()
+	defer p.Unlock()
+
+	prev := p.stage(value, false, nil)
+	if prev != nil {
+		p.state.source = meta.Source
+		p.state.headers = meta.Headers
+	}
+
+	p.notify(prev)
+}
+
+func (p *property[T]) UpdateFunc(fn func(old T) T) T {
+	p.read()
+
+	p.Lock()
+	defer p.Unlock()
+
+	if !p.ended {
+		p.update(fn(p.state.value))
+	}
+
+	return p.state.value
+}
+
+func (p *property[T]) CompareAndSwap(old, new T) bool {
+	p.read()
+
+	p.Lock()
+	defer p.Unlock()
+
+	if p.ended || any(p.state.value) != any(old) {
+		return false
+	}
+
+	p.update(new)
+
+	return true
+}
+
+func (p *property[T]) Observe() StreamOf[T] {
+	p.read()
+
+	p.RLock()
+	start := p.state
+	if p.opts.replay {
+		start = p.replayable(time.Now())
+	}
+	p.RUnlock()
+
+	return p.observe(start)
+}
+
+func (p *property[T]) ObserveFrom(n int) StreamOf[T] {
+	p.read()
+
+	p.RLock()
+	start := p.replayable(time.Now())
+	for start != p.state && p.state.seq-start.seq > uint64(n) {
+		start = start.next.Load()
+	}
+	p.RUnlock()
+
+	return p.observe(start)
+}
+
+func (p *property[T]) ObserveSince(t time.Time) StreamOf[T] {
+	p.read()
+
+	p.RLock()
+	start := p.replayable(time.Now())
+	for start != p.state && start.time.Before(t) {
+		start = start.next.Load()
+	}
+	p.RUnlock()
+
+	return p.observe(start)
+}
+
+func (p *property[T]) ObserveAt(seq uint64) (StreamOf[T], error) {
+	p.read()
+
+	p.RLock()
+	if seq > p.state.seq {
+		p.RUnlock()
+		return nil, ErrSeqOutOfRange
+	}
+
+	start := p.replayable(time.Now())
+	if seq < start.seq {
+		p.RUnlock()
+		return nil, ErrSeqNotRetained
+	}
+
+	for start.seq < seq {
+		start = start.next.Load()
+	}
+	p.RUnlock()
+
+	return p.observe(start), nil
+}
+
+// read runs the onRead hook, must be called before reading the value of this
+// property without holding the lock.
+func (p *property[T]) read() {
+	if p.onRead != nil {
+		p.onRead()
+	}
+}
+
+// observe returns a new stream starting at the given state, must be called
+// without holding the lock: registering the stream is left out of the
+// critical section so publishers are not held up by new streams.
+func (p *property[T]) observe(start *state[T]) StreamOf[T] {
+	return newStream(p, start)
+}
+
+// replayable returns the oldest retained state that has not expired at the
+// given time, must be called while holding the lock.
+func (p *property[T]) replayable(now time.Time) *state[T] {
+	if p.oldest == nil {
+		return p.state
+	}
+
+	start := p.oldest
+	for start != p.state && p.expired(start, now) {
+		start = start.next.Load()
+	}
+
+	return start
+}
+
+func (p *property[T]) WaitFor(ctx context.Context, predicate func(T) bool) (T, error) {
+	s := p.Observe()
+	defer s.Close()
+
+	return s.WaitUntil(ctx, predicate)
+}
+
+func (p *property[T]) End() {
+	p.Lock()
+	defer p.Unlock()
+
+	if !p.ended {
+		p.end(eof[T](), nil)
+	}
+}
+
+func (p *property[T]) Fail(err error) {
+	p.Lock()
+	defer p.Unlock()
+
+	if !p.ended {
+		p.end(eof[T](), err)
+	}
+}
+
+func (p *property[T]) Done() <-chan struct{} {
+	return p.done
+}
+
+func (p *property[T]) Err() error {
+	p.RLock()
+	defer p.RUnlock()
+
+	return p.err
+}
+
+// end appends the final state holding the given value, must be called while
+// holding the lock.
+func (p *property[T]) end(value T, err error) {
+	p.notify(p.stage(value, true, err))
+}
+
+// update appends the given value to the list of states, must be called while
+// holding the lock.
+func (p *property[T]) update(value T) {
+	p.notify(p.stage(value, false, nil))
+}
+
+// stage appends the given value to the list of states without notifying the
+// streams, ending the property if asked to or if the value is io.EOF. It must
+// be called while holding the lock, and returns the state that preceded the
+// new one to be given to notify, or nil if the property has already ended.
+func (p *property[T]) stage(value T, end bool, err error) *state[T] {
 	if p.ended {
+		return nil
+	}
+
+	next := newState(value)
+	if end || isEOF(value) {
+		next.ended, next.err = true, err
+		p.ended, p.err = true, err
+	}
+
+	prev := p.state
+	p.state = prev.stage(next)
+	p.trim()
+
+	return prev
+}
+
+// notify wakes up the streams waiting on the given state returned by stage,
+// must be called while holding the lock.
+func (p *property[T]) notify(prev *state[T]) {
+	if prev == nil {
 		return
 	}
 
-	for _, value := range values {
-		if value == io.EOF {
-			p.ended = true
+	close(prev.done)
+
+	if p.ended {
+		select {
+		case <-p.done:
+		default:
 			close(p.done)
 		}
-
-		p.state = p.state.update(value)
 	}
 }
 
-func (p *property) Observe() Stream {
-	p.RLock()
-	defer p.RUnlock()
-	return &stream{state: p.state}
+// trim discards the oldest retained states that exceed the history limits,
+// must be called while holding the lock. The link to a discarded state is cut
+// once the state that precedes it has been discarded too, so streams lagging
+// behind no longer keep them in memory.
+func (p *property[T]) trim() {
+	if p.oldest == nil {
+		return
+	}
+
+	now := p.state.time
+	for p.oldest != p.state && p.expired(p.oldest, now) {
+		if p.trimmed != nil {
+			p.trimmed.next.Store(nil)
+		}
+
+		p.trimmed = p.oldest
+		p.oldest = p.oldest.next.Load()
+	}
 }
 
-func (p *property) End() {
-	p.Update(io.EOF)
+// expired reports whether the given retained state exceeds the history limits
+// at the given time, must be called while holding the lock.
+func (p *property[T]) expired(s *state[T], now time.Time) bool {
+	if p.opts.historyLimit > 0 && p.state.seq-s.seq >= uint64(p.opts.historyLimit) {
+		return true
+	}
+
+	return p.opts.historyDuration > 0 && now.Sub(s.time) > p.opts.historyDuration
 }
 
-func (p *property) Done() <-chan struct{} {
-	return p.done
+// oldestRetained returns the oldest state retained in memory.
+func (p *property[T]) oldestRetained() *state[T] {
+	p.RLock()
+	defer p.RUnlock()
+
+	return p.oldest
 }
@@ -1,8 +1,12 @@
 package observer
 
 import (
+	"context"
+	"errors"
+	"io"
 	"sync"
 	"testing"
+	"time"
 )
 
 func TestPropertyInitialValue(t *testing.T) {
@@ -91,3 +95,390 @@ func TestPropertyMultipleConcurrentReadersWriters(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestPropertyOfInitialValue(t *testing.T) {
+	prop := NewPropertyOf("a")
+	if val := prop.Value(); val != "a" {
+		t.Fatalf("Expecting a but got %#v\n", val)
+	}
+	prop.Update("b", "c")
+	stream := prop.Observe()
+	if val := stream.Value(); val != "c" {
+		t.Fatalf("Expecting c but got %#v\n", val)
+	}
+}
+
+func TestPropertyOfEnd(t *testing.T) {
+	prop := NewPropertyOf(10)
+	stream := prop.Observe()
+	prop.Update(15)
+	prop.End()
+	prop.Update(20)
+	select {
+	case <-prop.Done():
+	default:
+		t.Fatalf("Expecting done\n")
+	}
+	if val := stream.Next(); val != 15 || stream.Ended() {
+		t.Fatalf("Expecting 15 but got %#v\n", val)
+	}
+	if val := stream.Next(); val != 0 || !stream.Ended() {
+		t.Fatalf("Expecting end of stream but got %#v\n", val)
+	}
+	if stream.HasNext() {
+		t.Fatalf("Expecting no changes\n")
+	}
+}
+
+func TestPropertyEndWithEOF(t *testing.T) {
+	prop := NewProperty(10)
+	stream := prop.Observe()
+	prop.Update(io.EOF, 15)
+	if val := prop.Value(); val != io.EOF {
+		t.Fatalf("Expecting io.EOF but got %#v\n", val)
+	}
+	if val := stream.Next(); val != io.EOF || !stream.Ended() {
+		t.Fatalf("Expecting end of stream but got %#v\n", val)
+	}
+}
+
+func TestPropertyFail(t *testing.T) {
+	failure := errors.New("failure")
+	prop := NewProperty(10)
+	stream := prop.Observe()
+	prop.Update(15)
+	prop.Fail(failure)
+	prop.End()
+	if err := prop.Err(); err != failure {
+		t.Fatalf("Expecting %#v but got %#v\n", failure, err)
+	}
+	if val := stream.Next(); val != 15 || stream.Err() != nil {
+		t.Fatalf("Expecting 15 but got %#v\n", val)
+	}
+	if val := stream.Next(); val != io.EOF || !stream.Ended() {
+		t.Fatalf("Expecting end of stream but got %#v\n", val)
+	}
+	if err := stream.Err(); err != failure {
+		t.Fatalf("Expecting %#v but got %#v\n", failure, err)
+	}
+}
+
+func TestPropertyEndHasNoErr(t *testing.T) {
+	prop := NewPropertyOf(10)
+	stream := prop.Observe()
+	prop.End()
+	prop.Fail(errors.New("failure"))
+	stream.Next()
+	if !stream.Ended() {
+		t.Fatalf("Expecting end of stream\n")
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatalf("Expecting no error but got %#v\n", err)
+	}
+	if err := prop.Err(); err != nil {
+		t.Fatalf("Expecting no error but got %#v\n", err)
+	}
+}
+
+func TestPropertyWaitFor(t *testing.T) {
+	prop := NewProperty(0)
+	go func() {
+		for i := 1; i <= 10; i++ {
+			prop.Update(i)
+		}
+	}()
+	val, err := prop.WaitFor(context.Background(), func(v interface{}) bool {
+		return v.(int) == 10
+	})
+	if err != nil || val != 10 {
+		t.Fatalf("Expecting 10 but got %#v, %#v\n", val, err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := prop.WaitFor(ctx, func(v interface{}) bool { return false }); err != context.Canceled {
+		t.Fatalf("Expecting context canceled but got %#v\n", err)
+	}
+	failure := errors.New("failure")
+	prop.Fail(failure)
+	if _, err := prop.WaitFor(context.Background(), func(v interface{}) bool { return false }); err != failure {
+		t.Fatalf("Expecting %#v but got %#v\n", failure, err)
+	}
+}
+
+func TestPropertyHistoryLimit(t *testing.T) {
+	prop := NewPropertyOf(0, WithHistoryLimit(3))
+	initial := prop.(*property[int]).state
+	slow := prop.Observe()
+	fast := prop.Observe()
+	prop.Update(1)
+	if val := fast.Next(); val != 1 || fast.Lagged() != nil {
+		t.Fatalf("Expecting 1 but got %#v\n", val)
+	}
+	for i := 2; i <= 10; i++ {
+		prop.Update(i)
+	}
+	if next := initial.next.Load(); next != nil {
+		t.Fatalf("Expecting discarded states to be unlinked but got %#v\n", next)
+	}
+	if val := slow.Next(); val != 8 {
+		t.Fatalf("Expecting 8 but got %#v\n", val)
+	}
+	if lagged := slow.Lagged(); lagged == nil || lagged.Skipped != 7 {
+		t.Fatalf("Expecting 7 skipped values but got %#v\n", lagged)
+	}
+	if val := slow.Next(); val != 9 || slow.Lagged() != nil {
+		t.Fatalf("Expecting 9 but got %#v\n", val)
+	}
+	if val := fast.Next(); val != 8 || fast.Lagged().Skipped != 6 {
+		t.Fatalf("Expecting 8 but got %#v\n", val)
+	}
+	prop.End()
+	if val := slow.WaitNext(); val != 10 {
+		t.Fatalf("Expecting 10 but got %#v\n", val)
+	}
+	slow.Next()
+	if !slow.Ended() {
+		t.Fatalf("Expecting end of stream\n")
+	}
+}
+
+func TestPropertyHistoryLimitConcurrentReaders(t *testing.T) {
+	prop := NewProperty(0, WithHistoryLimit(10))
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(stream Stream) {
+			defer wg.Done()
+			prev := stream.Value().(int)
+			for !stream.Ended() {
+				val := stream.WaitNext()
+				if val == io.EOF {
+					return
+				}
+				skipped := 0
+				if lagged := stream.Lagged(); lagged != nil {
+					skipped = lagged.Skipped
+				}
+				if expected := prev + 1 + skipped; val != expected {
+					t.Errorf("Expecting %#v but got %#v\n", expected, val)
+					return
+				}
+				prev = val.(int)
+			}
+		}(prop.Observe())
+	}
+	for i := 1; i <= 10000; i++ {
+		prop.Update(i)
+	}
+	prop.End()
+	wg.Wait()
+}
+
+func TestPropertyObserveFrom(t *testing.T) {
+	prop := NewPropertyOf(0, WithHistoryLimit(5))
+	for i := 1; i <= 10; i++ {
+		prop.Update(i)
+	}
+	stream := prop.ObserveFrom(2)
+	for _, expected := range []int{8, 9, 10} {
+		if val := stream.Value(); val != expected {
+			t.Fatalf("Expecting %#v but got %#v\n", expected, val)
+		}
+		if expected != 10 {
+			stream.Next()
+		}
+	}
+	if stream.HasNext() {
+		t.Fatalf("Expecting no changes\n")
+	}
+	if val := prop.ObserveFrom(100).Value(); val != 6 {
+		t.Fatalf("Expecting 6 but got %#v\n", val)
+	}
+	if val := prop.ObserveFrom(0).Value(); val != 10 {
+		t.Fatalf("Expecting 10 but got %#v\n", val)
+	}
+	if val := NewPropertyOf(1).ObserveFrom(10).Value(); val != 1 {
+		t.Fatalf("Expecting 1 but got %#v\n", val)
+	}
+}
+
+func TestPropertyObserveSince(t *testing.T) {
+	prop := NewPropertyOf(0, WithHistoryDuration(time.Hour))
+	prop.Update(1, 2)
+	since := time.Now()
+	time.Sleep(10 * time.Millisecond)
+	prop.Update(3, 4)
+	stream := prop.ObserveSince(since)
+	if val := stream.Value(); val != 3 {
+		t.Fatalf("Expecting 3 but got %#v\n", val)
+	}
+	if val := stream.Next(); val != 4 {
+		t.Fatalf("Expecting 4 but got %#v\n", val)
+	}
+	if val := prop.ObserveSince(time.Now()).Value(); val != 4 {
+		t.Fatalf("Expecting 4 but got %#v\n", val)
+	}
+}
+
+func TestPropertyHistoryDuration(t *testing.T) {
+	prop := NewPropertyOf(0, WithHistoryDuration(50*time.Millisecond), WithReplay())
+	prop.Update(1, 2)
+	if val := prop.Observe().Value(); val != 0 {
+		t.Fatalf("Expecting 0 but got %#v\n", val)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if val := prop.Observe().Value(); val != 2 {
+		t.Fatalf("Expecting 2 but got %#v\n", val)
+	}
+	prop.Update(3)
+	if oldest := prop.(*property[int]).oldest.value; oldest != 3 {
+		t.Fatalf("Expecting 3 but got %#v\n", oldest)
+	}
+}
+
+func TestPropertyReplay(t *testing.T) {
+	prop := NewProperty(0, WithHistoryLimit(3), WithReplay())
+	for i := 1; i <= 5; i++ {
+		prop.Update(i)
+	}
+	prop.End()
+	stream := prop.Observe()
+	for _, expected := range []interface{}{4, 5, io.EOF} {
+		if val := stream.Value(); val != expected {
+			t.Fatalf("Expecting %#v but got %#v\n", expected, val)
+		}
+		if expected != io.EOF {
+			stream.Next()
+		}
+	}
+}
+
+func TestPropertyObserveAt(t *testing.T) {
+	prop := NewPropertyOf(0, WithHistoryLimit(5))
+	for i := 1; i <= 10; i++ {
+		prop.Update(i * 10)
+	}
+	stream, err := prop.ObserveAt(7)
+	if err != nil {
+		t.Fatalf("Expecting no error but got %#v\n", err)
+	}
+	if val := stream.Value(); val != 70 || stream.Seq() != 7 {
+		t.Fatalf("Expecting 70 but got %#v\n", val)
+	}
+	if val := stream.Next(); val != 80 || stream.Seq() != 8 {
+		t.Fatalf("Expecting 80 but got %#v\n", val)
+	}
+	if _, err := prop.ObserveAt(5); err != ErrSeqNotRetained {
+		t.Fatalf("Expecting ErrSeqNotRetained but got %#v\n", err)
+	}
+	if _, err := prop.ObserveAt(11); err != ErrSeqOutOfRange {
+		t.Fatalf("Expecting ErrSeqOutOfRange but got %#v\n", err)
+	}
+	if stream, err := prop.ObserveAt(10); err != nil || stream.Value() != 100 {
+		t.Fatalf("Expecting 100 but got %#v\n", err)
+	}
+}
+
+func TestPropertyUpdateFunc(t *testing.T) {
+	prop := NewPropertyOf(0)
+	stream := prop.Observe()
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				prop.UpdateFunc(func(old int) int {
+					return old + 1
+				})
+			}
+		}()
+	}
+	wg.Wait()
+	if val := prop.Value(); val != 10000 {
+		t.Fatalf("Expecting 10000 but got %#v\n", val)
+	}
+	for i := 1; i <= 10000; i++ {
+		if val := stream.Next(); val != i {
+			t.Fatalf("Expecting %#v but got %#v\n", i, val)
+		}
+	}
+	prop.End()
+	if val := prop.UpdateFunc(func(old int) int { return old + 1 }); val != 0 {
+		t.Fatalf("Expecting 0 but got %#v\n", val)
+	}
+}
+
+func TestPropertyCompareAndSwap(t *testing.T) {
+	prop := NewProperty(10)
+	if prop.CompareAndSwap(15, 20) {
+		t.Fatalf("Expecting no swap\n")
+	}
+	if !prop.CompareAndSwap(10, 20) {
+		t.Fatalf("Expecting swap\n")
+	}
+	if val := prop.Value(); val != 20 {
+		t.Fatalf("Expecting 20 but got %#v\n", val)
+	}
+	if !prop.CompareAndSwap(20, io.EOF) {
+		t.Fatalf("Expecting swap\n")
+	}
+	if prop.CompareAndSwap(io.EOF, 30) {
+		t.Fatalf("Expecting no swap once ended\n")
+	}
+	select {
+	case <-prop.Done():
+	default:
+		t.Fatalf("Expecting done\n")
+	}
+}
+
+func TestStartSeq(t *testing.T) {
+	prop := NewProperty(1, WithStartSeq(41))
+	stream := prop.Observe()
+	prop.Update(2)
+	if seq := stream.Seq(); seq != 41 {
+		t.Fatalf("Expecting 41 but got %#v\n", seq)
+	}
+	if stream.Next(); stream.Seq() != 42 {
+		t.Fatalf("Expecting 42 but got %#v\n", stream.Seq())
+	}
+	if _, err := prop.ObserveAt(40); err != ErrSeqNotRetained {
+		t.Fatalf("Expecting ErrSeqNotRetained but got %#v\n", err)
+	}
+}
+
+func TestPropertyUpdateWithMeta(t *testing.T) {
+	prop := NewProperty(1)
+	stream := prop.Observe()
+	if meta := stream.Meta(); meta.Seq != 0 || meta.Source != "" || meta.Time.IsZero() {
+		t.Fatalf("Expecting the meta of the initial value but got %#v\n", meta)
+	}
+
+	headers := map[string]string{"trace-id": "abc"}
+	before := time.Now()
+	prop.UpdateWithMeta(2, Meta{Source: "writer-1", Headers: headers, Seq: 42})
+	prop.Update(3)
+
+	if val := stream.Next(); val != 2 {
+		t.Fatalf("Expecting 2 but got %#v\n", val)
+	}
+	meta := stream.Meta()
+	if meta.Source != "writer-1" || meta.Headers["trace-id"] != "abc" || meta.Seq != 1 {
+		t.Fatalf("Expecting the meta of writer-1 but got %#v\n", meta)
+	}
+	if meta.Time.Before(before) {
+		t.Fatalf("Expecting the update time but got %#v\n", meta.Time)
+	}
+
+	stream.Next()
+	if meta := stream.Meta(); meta.Source != "" || meta.Headers != nil || meta.Seq != 2 {
+		t.Fatalf("Expecting no source nor headers but got %#v\n", meta)
+	}
+
+	prop.End()
+	prop.UpdateWithMeta(4, Meta{Source: "writer-1"})
+	if val := prop.Value(); val != io.EOF {
+		t.Fatalf("Expecting io.EOF but got %#v\n", val)
+	}
+}
@@ -0,0 +1,235 @@
+package observer
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrInvalidTopic is returned when a topic or a topic pattern is malformed.
+var ErrInvalidTopic = errors.New("observer: invalid topic")
+
+// TopicValue is a value of the merged streams returned by Registry.Observe,
+// tagged with the topic of the property it comes from.
+type TopicValue struct {
+	Topic string
+	Value interface{}
+}
+
+// Registry maps hierarchical topics to properties, creating them on demand.
+// Topics are made of levels separated by slashes, e.g. "orders/eu/created",
+// and can be observed with patterns where a "*" level matches any single
+// level, and a trailing "#" level matches any number of levels, including
+// none. It is completely goroutine safe.
+type Registry struct {
+	opts []Option
+
+	mu            sync.Mutex
+	properties    map[string]Property
+	subscriptions map[*subscription]struct{}
+}
+
+// NewRegistry creates an empty Registry, the given options are used to create
+// its properties.
+func NewRegistry(opts ...Option) *Registry {
+	return &Registry{
+		opts:          opts,
+		properties:    make(map[string]Property),
+		subscriptions: make(map[*subscription]struct{}),
+	}
+}
+
+// Get is like Lookup but panics if the topic is malformed, it simplifies
+// getting the properties of topics known in advance.
+func (r *Registry) Get(topic string) Property {
+	p, err := r.Lookup(topic)
+	if err != nil {
+		panic(err)
+	}
+
+	return p
+}
+
+// Lookup returns the property for the given topic, creating it with a nil
+// value if it doesn't exist yet. It returns ErrInvalidTopic if the topic has
+// empty levels or holds wildcards.
+func (r *Registry) Lookup(topic string) (Property, error) {
+	levels, err := split(topic, false)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if p, ok := r.properties[topic]; ok {
+		return p, nil
+	}
+
+	p := NewProperty(nil, r.opts...)
+	r.properties[topic] = p
+
+	for s := range r.subscriptions {
+		if match(s.pattern, levels) {
+			s.attach(topic, p)
+		}
+	}
+
+	return p, nil
+}
+
+// Topics returns the sorted topics of the properties in this registry.
+func (r *Registry) Topics() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	topics := make([]string, 0, len(r.properties))
+	for topic := range r.properties {
+		topics = append(topics, topic)
+	}
+
+	sort.Strings(topics)
+
+	return topics
+}
+
+// Observe returns a stream merging the values of every property whose topic
+// matches the given pattern, including the ones created afterwards. The stream
+// starts at an empty TopicValue, followed by the current value of each
+// matching property in topic order, and then by their updates as they happen.
+// Properties that end are left out of the merged stream, which never ends on
+// its own.
+//
+// The properties are followed until the stream, and every clone of it, are
+// closed or garbage collected. It returns ErrInvalidTopic if the pattern has
+// empty levels, or if a "#" level is not the last one.
+func (r *Registry) Observe(pattern string) (StreamOf[TopicValue], error) {
+	levels, err := split(pattern, true)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &subscription{
+		registry: r,
+		pattern:  levels,
+		property: NewPropertyOf(TopicValue{}).(*property[TopicValue]),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+
+	s.property.onObservers = s.refresh
+	stream := s.property.Observe()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	topics := make([]string, 0)
+	for topic := range r.properties {
+		if match(levels, strings.Split(topic, "/")) {
+			topics = append(topics, topic)
+		}
+	}
+
+	sort.Strings(topics)
+	for _, topic := range topics {
+		s.attach(topic, r.properties[topic])
+	}
+
+	r.subscriptions[s] = struct{}{}
+
+	return stream, nil
+}
+
+// split returns the levels of the given topic, or of the given pattern if
+// wildcards are allowed. It returns ErrInvalidTopic if the topic is malformed.
+func split(topic string, wildcards bool) ([]string, error) {
+	levels := strings.Split(topic, "/")
+	for i, level := range levels {
+		switch {
+		case level == "":
+			return nil, ErrInvalidTopic
+		case (level == "*" || level == "#") && !wildcards:
+			return nil, ErrInvalidTopic
+		case level == "#" && i != len(levels)-1:
+			return nil, ErrInvalidTopic
+		}
+	}
+
+	return levels, nil
+}
+
+// match reports whether the given topic levels match the given pattern levels.
+func match(pattern, topic []string) bool {
+	for i, level := range pattern {
+		if level == "#" {
+			return true
+		}
+
+		if i >= len(topic) || (level != "*" && level != topic[i]) {
+			return false
+		}
+	}
+
+	return len(pattern) == len(topic)
+}
+
+// subscription feeds the merged stream returned by Registry.Observe.
+type subscription struct {
+	registry *Registry
+	pattern  []string
+	property *property[TopicValue]
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	once   sync.Once
+}
+
+// attach adds the current value of the given property to the merged stream
+// and follows its updates, must be called while holding the registry lock so
+// values are merged in the order properties are attached.
+func (s *subscription) attach(topic string, p Property) {
+	source := p.Observe()
+	if source.Ended() {
+		source.Close()
+		return
+	}
+
+	s.property.Update(TopicValue{Topic: topic, Value: source.Value()})
+
+	go s.forward(topic, source)
+}
+
+// forward merges the updates of the given source stream until it ends or the
+// subscription stops.
+func (s *subscription) forward(topic string, source Stream) {
+	defer source.Close()
+
+	for {
+		value, err := source.WaitNextContext(s.ctx)
+		if err != nil || source.Ended() {
+			return
+		}
+
+		s.property.Update(TopicValue{Topic: topic, Value: value})
+	}
+}
+
+// refresh stops the subscription once the merged stream is no longer observed.
+func (s *subscription) refresh() {
+	if s.property.observed() {
+		return
+	}
+
+	s.once.Do(func() {
+		s.cancel()
+		s.property.End()
+
+		s.registry.mu.Lock()
+		defer s.registry.mu.Unlock()
+
+		delete(s.registry.subscriptions, s)
+	})
+}
@@ -0,0 +1,160 @@
+package observer
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// next waits for the next value of the given stream, failing after a second.
+func next(t *testing.T, stream StreamOf[TopicValue]) TopicValue {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	val, err := stream.WaitNextContext(ctx)
+	if err != nil {
+		t.Fatalf("Expecting a value but got %#v\n", err)
+	}
+
+	return val
+}
+
+func TestRegistryGet(t *testing.T) {
+	registry := NewRegistry()
+	prop := registry.Get("orders/eu/created")
+	if val := prop.Value(); val != nil {
+		t.Fatalf("Expecting nil but got %#v\n", val)
+	}
+	if registry.Get("orders/eu/created") != prop {
+		t.Fatalf("Expecting the same property\n")
+	}
+
+	registry.Get("orders/us/created")
+	topics := registry.Topics()
+	if !reflect.DeepEqual(topics, []string{"orders/eu/created", "orders/us/created"}) {
+		t.Fatalf("Expecting both topics but got %#v\n", topics)
+	}
+
+	defer func() {
+		if err := recover(); err != ErrInvalidTopic {
+			t.Fatalf("Expecting ErrInvalidTopic but got %#v\n", err)
+		}
+	}()
+
+	registry.Get("orders/*/created")
+}
+
+func TestRegistryLookup(t *testing.T) {
+	registry := NewRegistry()
+	prop, err := registry.Lookup("orders/eu/created")
+	if err != nil || prop != registry.Get("orders/eu/created") {
+		t.Fatalf("Expecting the same property but got %#v\n", err)
+	}
+
+	for _, topic := range []string{"", "orders//created", "orders/eu/", "orders/*/created", "orders/#"} {
+		if _, err := registry.Lookup(topic); err != ErrInvalidTopic {
+			t.Fatalf("Expecting ErrInvalidTopic for %q but got %#v\n", topic, err)
+		}
+	}
+
+	if topics := registry.Topics(); len(topics) != 1 {
+		t.Fatalf("Expecting a single topic but got %#v\n", topics)
+	}
+}
+
+func TestRegistryMatch(t *testing.T) {
+	cases := []struct {
+		pattern string
+		topic   string
+		match   bool
+	}{
+		{"orders/eu/created", "orders/eu/created", true},
+		{"orders/eu/created", "orders/us/created", false},
+		{"orders/*/created", "orders/eu/created", true},
+		{"orders/*/created", "orders/eu/deleted", false},
+		{"orders/*", "orders/eu/created", false},
+		{"orders/#", "orders/eu/created", true},
+		{"orders/#", "orders", true},
+		{"orders/#", "users/eu", false},
+		{"#", "orders/eu/created", true},
+		{"*/eu/#", "orders/eu/created", true},
+	}
+
+	for _, c := range cases {
+		if got := match(strings.Split(c.pattern, "/"), strings.Split(c.topic, "/")); got != c.match {
+			t.Fatalf("Expecting %q to match %q to be %v\n", c.pattern, c.topic, c.match)
+		}
+	}
+}
+
+func TestRegistryObserve(t *testing.T) {
+	registry := NewRegistry()
+	registry.Get("orders/us/created").Update(1)
+	registry.Get("orders/eu/created").Update(2)
+	registry.Get("orders/eu/deleted").Update(3)
+
+	stream, err := registry.Observe("orders/*/created")
+	if err != nil {
+		t.Fatalf("Expecting no error but got %#v\n", err)
+	}
+	if val := stream.Value(); val != (TopicValue{}) {
+		t.Fatalf("Expecting an empty value but got %#v\n", val)
+	}
+	if val := next(t, stream); val != (TopicValue{"orders/eu/created", 2}) {
+		t.Fatalf("Expecting orders/eu/created but got %#v\n", val)
+	}
+	if val := next(t, stream); val != (TopicValue{"orders/us/created", 1}) {
+		t.Fatalf("Expecting orders/us/created but got %#v\n", val)
+	}
+
+	registry.Get("orders/eu/deleted").Update(4)
+	registry.Get("orders/us/created").Update(5)
+	if val := next(t, stream); val != (TopicValue{"orders/us/created", 5}) {
+		t.Fatalf("Expecting orders/us/created but got %#v\n", val)
+	}
+
+	registry.Get("orders/asia/created").Update(6)
+	if val := next(t, stream); val != (TopicValue{"orders/asia/created", nil}) {
+		t.Fatalf("Expecting the initial value of orders/asia/created but got %#v\n", val)
+	}
+	if val := next(t, stream); val != (TopicValue{"orders/asia/created", 6}) {
+		t.Fatalf("Expecting orders/asia/created but got %#v\n", val)
+	}
+}
+
+func TestRegistryObserveInvalid(t *testing.T) {
+	registry := NewRegistry()
+	for _, pattern := range []string{"", "orders/#/created", "orders//created", "orders/*/"} {
+		if _, err := registry.Observe(pattern); err != ErrInvalidTopic {
+			t.Fatalf("Expecting ErrInvalidTopic for %q but got %#v\n", pattern, err)
+		}
+	}
+}
+
+func TestRegistryObserveClose(t *testing.T) {
+	registry := NewRegistry()
+	prop := registry.Get("orders/eu/created")
+
+	stream, _ := registry.Observe("orders/#")
+	next(t, stream)
+	if n := prop.Stats().Streams; n != 1 {
+		t.Fatalf("Expecting 1 stream but got %#v\n", n)
+	}
+
+	stream.Close()
+	deadline := time.Now().Add(time.Second)
+	for prop.Stats().Streams != 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expecting the property not to be observed anymore\n")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	registry.Get("orders/us/created")
+	if n := len(registry.subscriptions); n != 0 {
+		t.Fatalf("Expecting no subscriptions but got %#v\n", n)
+	}
+}
@@ -0,0 +1,218 @@
+package replication
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/botchris/go-observer"
+	"github.com/botchris/go-observer/persist"
+)
+
+// RemoteError is the error a mirror fails with when the remote property fails,
+// or when the server refuses to publish it.
+type RemoteError struct {
+	Message string
+}
+
+func (e *RemoteError) Error() string {
+	return "replication: " + e.Message
+}
+
+// Mirror is a local property following a property published by a Server. It
+// holds the current value of the remote property right away, and is updated
+// as the remote property is. It ends when the remote property ends, and fails
+// with a RemoteError if it fails, or with the context error once the context
+// given to NewMirror is done. Ending the mirror, or failing it, disconnects it
+// from the server.
+//
+// Mirrors are meant to be observed, not updated: local updates are overwritten
+// by the next remote update.
+type Mirror[T any] struct {
+	observer.PropertyOf[T]
+
+	stale observer.PropertyOf[bool]
+}
+
+// NewMirror connects to a server with the given dialer and returns a mirror of
+// the property published under the given name. It returns an error if the
+// first connection fails, or a RemoteError if the server doesn't publish such
+// a property.
+//
+// When the connection drops the mirror is marked as stale, see Mirror.Stale,
+// and it reconnects after the retry delay, see WithRetry. It then resumes with
+// the values that follow the last one it received, as long as the remote
+// property retains them, or the current value otherwise.
+func NewMirror[T any](ctx context.Context, dial Dialer, name string, opts ...Option) (*Mirror[T], error) {
+	c := &client[T]{
+		dial: dial,
+		name: name,
+		opts: newOptions(opts),
+	}
+
+	dec, err := c.connect(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var msg message[T]
+	if err := dec.Decode(&msg); err != nil {
+		c.conn.Close()
+		return nil, err
+	}
+
+	m := &Mirror[T]{
+		PropertyOf: observer.NewPropertyOf(msg.Value, observer.WithStartSeq(msg.Seq)),
+		stale:      observer.NewPropertyOf(false),
+	}
+
+	c.seq = msg.Seq
+	if msg.End {
+		c.apply(m, msg)
+		c.conn.Close()
+		m.stale.End()
+
+		return m, nil
+	}
+
+	go c.run(ctx, m, dec)
+
+	return m, nil
+}
+
+// Stale returns a property telling whether this mirror is disconnected from
+// the server, in which case its value may be outdated. It ends along with the
+// mirror.
+func (m *Mirror[T]) Stale() observer.PropertyOf[bool] {
+	return m.stale
+}
+
+type client[T any] struct {
+	dial Dialer
+	name string
+	opts *options
+	conn net.Conn
+	seq  uint64
+}
+
+// connect opens a new connection and sends the hello message, resuming from
+// the last value received if asked to.
+func (c *client[T]) connect(ctx context.Context, resume bool) (persist.Decoder, error) {
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := c.opts.codec.NewDecoder(conn)
+	enc := c.opts.codec.NewEncoder(conn)
+
+	if err := enc.Encode(hello{Name: c.name, Resume: resume, Seq: c.seq}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	var a ack
+	if err := dec.Decode(&a); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if a.Err != "" {
+		conn.Close()
+		return nil, &RemoteError{Message: a.Err}
+	}
+
+	c.conn = conn
+
+	return dec, nil
+}
+
+// run updates the mirror with the messages received, reconnecting when the
+// connection drops, until the mirror is done or ctx is.
+func (c *client[T]) run(ctx context.Context, m *Mirror[T], dec persist.Decoder) {
+	defer m.stale.End()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		select {
+		case <-m.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for {
+		if dec != nil {
+			conn := c.conn
+			stop := context.AfterFunc(ctx, func() {
+				conn.Close()
+			})
+
+			done := c.follow(m, dec)
+			stop()
+			conn.Close()
+
+			if done {
+				return
+			}
+
+			m.stale.Update(true)
+		}
+
+		select {
+		case <-ctx.Done():
+			m.Fail(ctx.Err())
+			return
+		case <-time.After(c.opts.retry):
+		}
+
+		var err error
+		if dec, err = c.connect(ctx, true); err != nil {
+			var remote *RemoteError
+			if errors.As(err, &remote) {
+				m.Fail(err)
+				return
+			}
+
+			continue
+		}
+
+		m.stale.Update(false)
+	}
+}
+
+// follow applies the messages received until the connection drops, it
+// reports whether the mirror is done.
+func (c *client[T]) follow(m *Mirror[T], dec persist.Decoder) bool {
+	for {
+		var msg message[T]
+		if err := dec.Decode(&msg); err != nil {
+			return false
+		}
+
+		if c.apply(m, msg) {
+			return true
+		}
+	}
+}
+
+// apply updates the mirror with the given message, it reports whether the
+// mirror is done.
+func (c *client[T]) apply(m *Mirror[T], msg message[T]) bool {
+	switch {
+	case msg.End && msg.Err != "":
+		m.Fail(&RemoteError{Message: msg.Err})
+		return true
+	case msg.End:
+		m.End()
+		return true
+	}
+
+	m.Update(msg.Value)
+	c.seq = msg.Seq
+
+	return false
+}
@@ -0,0 +1,223 @@
+package replication_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/botchris/go-observer"
+	"github.com/botchris/go-observer/persist"
+	"github.com/botchris/go-observer/replication"
+	"github.com/stretchr/testify/require"
+)
+
+type point struct {
+	X, Y int
+}
+
+// pipe returns a dialer serving every connection with the given server over
+// an in-memory pipe, along with a func dropping the open connections.
+func pipe(server *replication.Server) (replication.Dialer, func()) {
+	var mu sync.Mutex
+	var conns []net.Conn
+
+	dial := func(ctx context.Context) (net.Conn, error) {
+		client, conn := net.Pipe()
+		go server.ServeConn(conn)
+
+		mu.Lock()
+		conns = append(conns, client)
+		mu.Unlock()
+
+		return client, nil
+	}
+
+	drop := func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		for _, conn := range conns {
+			conn.Close()
+		}
+
+		conns = nil
+	}
+
+	return dial, drop
+}
+
+func TestMirror(t *testing.T) {
+	t.Run("GIVEN a published property WHEN mirrored THEN the mirror follows its updates until the end", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		prop := observer.NewPropertyOf(point{1, 1})
+		server := replication.NewServer(replication.WithCodec(persist.Gob))
+		defer server.Close()
+		replication.Publish(server, "point", prop)
+
+		dial, _ := pipe(server)
+		mirror, err := replication.NewMirror[point](ctx, dial, "point", replication.WithCodec(persist.Gob))
+		require.NoError(t, err)
+		require.Equal(t, point{1, 1}, mirror.Value())
+		require.False(t, mirror.Stale().Value())
+
+		stream := mirror.Observe()
+		prop.Update(point{2, 2})
+		require.Equal(t, point{2, 2}, stream.WaitNext())
+		require.Equal(t, uint64(1), stream.Seq())
+
+		prop.End()
+		stream.WaitNext()
+		require.True(t, stream.Ended())
+		require.NoError(t, stream.Err())
+
+		<-mirror.Stale().Done()
+	})
+
+	t.Run("GIVEN a failed property WHEN mirrored THEN the mirror fails with a remote error", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		prop := observer.NewPropertyOf(0)
+		server := replication.NewServer()
+		defer server.Close()
+		replication.Publish(server, "count", prop)
+
+		dial, _ := pipe(server)
+		mirror, err := replication.NewMirror[int](ctx, dial, "count")
+		require.NoError(t, err)
+
+		prop.Fail(errors.New("failure"))
+		<-mirror.Done()
+		require.Equal(t, &replication.RemoteError{Message: "failure"}, mirror.Err())
+	})
+
+	t.Run("GIVEN an unknown property WHEN mirrored THEN a remote error is returned", func(t *testing.T) {
+		server := replication.NewServer()
+		defer server.Close()
+
+		dial, _ := pipe(server)
+		_, err := replication.NewMirror[int](context.Background(), dial, "unknown")
+
+		var remote *replication.RemoteError
+		require.True(t, errors.As(err, &remote))
+	})
+
+	t.Run("GIVEN a dropped connection WHEN reconnected THEN the mirror is stale meanwhile and resumes", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		prop := observer.NewPropertyOf(0, observer.WithHistoryLimit(100))
+		server := replication.NewServer()
+		defer server.Close()
+		replication.Publish(server, "count", prop)
+
+		dial, drop := pipe(server)
+		mirror, err := replication.NewMirror[int](ctx, dial, "count", replication.WithRetry(50*time.Millisecond))
+		require.NoError(t, err)
+
+		stream := mirror.Observe()
+		stale := mirror.Stale().Observe()
+
+		prop.Update(1)
+		require.Equal(t, 1, stream.WaitNext())
+
+		drop()
+		require.True(t, stale.WaitNext())
+		prop.Update(2, 3)
+		require.False(t, stale.WaitNext())
+
+		require.Equal(t, 2, stream.WaitNext())
+		require.Equal(t, 3, stream.WaitNext())
+		require.Equal(t, uint64(3), stream.Seq())
+	})
+
+	t.Run("GIVEN a mirror WHEN context is cancelled THEN it fails with the context error", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		server := replication.NewServer()
+		defer server.Close()
+		replication.Publish(server, "count", observer.NewPropertyOf(0))
+
+		dial, _ := pipe(server)
+		mirror, err := replication.NewMirror[int](ctx, dial, "count")
+		require.NoError(t, err)
+
+		cancel()
+		<-mirror.Done()
+		require.Equal(t, context.Canceled, mirror.Err())
+	})
+	t.Run("GIVEN a mirror WHEN it is ended THEN it disconnects from the server", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		prop := observer.NewPropertyOf(0)
+		server := replication.NewServer()
+		defer server.Close()
+		replication.Publish(server, "count", prop)
+
+		dial, _ := pipe(server)
+		mirror, err := replication.NewMirror[int](ctx, dial, "count")
+		require.NoError(t, err)
+		require.Equal(t, 1, prop.Stats().Streams)
+
+		mirror.End()
+
+		select {
+		case <-mirror.Stale().Done():
+		case <-time.After(time.Second):
+			t.Fatal("Expecting the mirror to disconnect")
+		}
+
+		require.Eventually(t, func() bool {
+			return prop.Stats().Streams == 0
+		}, time.Second, time.Millisecond)
+		require.NoError(t, mirror.Err())
+	})
+}
+
+func TestServer(t *testing.T) {
+	t.Run("GIVEN a server listening on a unix socket WHEN mirrored THEN values are replicated", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		dir, err := os.MkdirTemp("", "replication")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		path := filepath.Join(dir, "sock")
+		l, err := net.Listen("unix", path)
+		require.NoError(t, err)
+
+		prop := observer.NewPropertyOf("a")
+		server := replication.NewServer()
+		replication.Publish(server, "letter", prop)
+
+		served := make(chan error)
+		go func() {
+			served <- server.Serve(l)
+		}()
+
+		mirror, err := replication.NewMirror[string](ctx, replication.Dial("unix", path), "letter")
+		require.NoError(t, err)
+		require.Equal(t, "a", mirror.Value())
+
+		stream := mirror.Observe()
+		prop.Update("b")
+		require.Equal(t, "b", stream.WaitNext())
+
+		require.NoError(t, server.Close())
+		require.Equal(t, replication.ErrServerClosed, <-served)
+		stale, err := mirror.Stale().WaitFor(ctx, func(stale bool) bool {
+			return stale
+		})
+		require.NoError(t, err)
+		require.True(t, stale)
+	})
+}
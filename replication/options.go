@@ -0,0 +1,57 @@
+package replication
+
+import (
+	"time"
+
+	"github.com/botchris/go-observer/persist"
+)
+
+// Option handles configurable server and mirror options.
+type Option interface {
+	apply(*options)
+}
+
+type options struct {
+	codec persist.Codec
+	retry time.Duration
+}
+
+type funcOption struct {
+	fn func(*options)
+}
+
+func (f *funcOption) apply(o *options) {
+	f.fn(o)
+}
+
+// WithCodec sets how messages are serialized on the wire, defaults to persist.JSON. Servers and mirrors must agree on
+// the codec.
+func WithCodec(codec persist.Codec) Option {
+	return &funcOption{
+		fn: func(o *options) {
+			o.codec = codec
+		},
+	}
+}
+
+// WithRetry sets how long a mirror waits before reconnecting after the connection drops, defaults to one second.
+func WithRetry(d time.Duration) Option {
+	return &funcOption{
+		fn: func(o *options) {
+			o.retry = d
+		},
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		codec: persist.JSON,
+		retry: time.Second,
+	}
+
+	for _, opt := range opts {
+		opt.apply(o)
+	}
+
+	return o
+}
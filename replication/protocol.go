@@ -0,0 +1,46 @@
+// Package replication shares properties between processes over any byte-stream
+// transport, such as unix sockets or TCP on loopback. A Server publishes named
+// properties, and remote processes follow them through a local Mirror.
+//
+// Every connection follows a single property: the mirror sends a hello message
+// naming the property, and where to resume from when reconnecting. The server
+// answers with an ack message, then streams the values of the property until
+// it ends or the connection is closed.
+package replication
+
+import (
+	"context"
+	"net"
+)
+
+// hello is the first message sent by a mirror.
+type hello struct {
+	Name   string
+	Resume bool
+	Seq    uint64
+}
+
+// ack is the answer of the server to a hello message, Err is set when the
+// property cannot be followed.
+type ack struct {
+	Err string
+}
+
+// message carries a value of the property, or its end.
+type message[T any] struct {
+	Seq   uint64
+	Value T
+	End   bool
+	Err   string
+}
+
+// Dialer opens a connection to a server.
+type Dialer func(ctx context.Context) (net.Conn, error)
+
+// Dial returns a Dialer connecting to the given address, see net.Dial.
+func Dial(network, address string) Dialer {
+	return func(ctx context.Context) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, network, address)
+	}
+}
@@ -0,0 +1,212 @@
+package replication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/botchris/go-observer"
+	"github.com/botchris/go-observer/persist"
+)
+
+// ErrServerClosed is returned by Server.Serve once the server is closed.
+var ErrServerClosed = errors.New("replication: server closed")
+
+// Server publishes named properties to the mirrors connecting to it. It is
+// goroutine safe.
+type Server struct {
+	opts   *options
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu         sync.RWMutex
+	properties map[string]publisher
+	listeners  map[net.Listener]struct{}
+}
+
+// publisher streams the values of a published property over a connection.
+type publisher interface {
+	publish(ctx context.Context, enc persist.Encoder, h hello) error
+}
+
+// NewServer creates a server with no published properties.
+func NewServer(opts ...Option) *Server {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Server{
+		opts:       newOptions(opts),
+		ctx:        ctx,
+		cancel:     cancel,
+		properties: make(map[string]publisher),
+		listeners:  make(map[net.Listener]struct{}),
+	}
+}
+
+// Publish makes the given property available to mirrors under the given name,
+// replacing the property previously published under that name if any. Mirrors
+// already following the previous property are not affected.
+func Publish[T any](s *Server, name string, p observer.PropertyOf[T]) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.properties[name] = &property[T]{PropertyOf: p}
+}
+
+// Unpublish removes the property published under the given name, mirrors
+// already following it are not affected.
+func (s *Server) Unpublish(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.properties, name)
+}
+
+// Serve accepts connections on the given listener and serves each of them on
+// its own goroutine, until the listener fails or the server is closed. It
+// returns ErrServerClosed once the server is closed.
+func (s *Server) Serve(l net.Listener) error {
+	s.mu.Lock()
+	if s.ctx.Err() != nil {
+		s.mu.Unlock()
+		return ErrServerClosed
+	}
+
+	s.listeners[l] = struct{}{}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.listeners, l)
+		s.mu.Unlock()
+	}()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if s.ctx.Err() != nil {
+				return ErrServerClosed
+			}
+
+			return err
+		}
+
+		go s.ServeConn(conn)
+	}
+}
+
+// ServeConn serves a single connection until the mirror disconnects, the
+// property it follows ends, or the server is closed. The connection is closed
+// on return.
+func (s *Server) ServeConn(conn net.Conn) {
+	ctx, cancel := context.WithCancel(s.ctx)
+	defer cancel()
+
+	stop := context.AfterFunc(ctx, func() {
+		conn.Close()
+	})
+	defer stop()
+	defer conn.Close()
+
+	dec := s.opts.codec.NewDecoder(conn)
+	enc := s.opts.codec.NewEncoder(conn)
+
+	var h hello
+	if err := dec.Decode(&h); err != nil {
+		return
+	}
+
+	s.mu.RLock()
+	pub, ok := s.properties[h.Name]
+	s.mu.RUnlock()
+
+	if !ok {
+		enc.Encode(ack{Err: fmt.Sprintf("unknown property %q", h.Name)})
+		return
+	}
+
+	if err := enc.Encode(ack{}); err != nil {
+		return
+	}
+
+	// mirrors don't send anything after the hello message, reading only
+	// tells when they disconnect.
+	go func() {
+		var b [1]byte
+		conn.Read(b[:])
+		cancel()
+	}()
+
+	pub.publish(ctx, enc, h)
+}
+
+// Close stops the server: listeners are closed and connections are dropped.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cancel()
+
+	var errs []error
+	for l := range s.listeners {
+		errs = append(errs, l.Close())
+	}
+
+	return errors.Join(errs...)
+}
+
+type property[T any] struct {
+	observer.PropertyOf[T]
+}
+
+func (p *property[T]) publish(ctx context.Context, enc persist.Encoder, h hello) error {
+	s, resumed := p.observe(h)
+	defer s.Close()
+
+	if !resumed {
+		if err := p.send(enc, s); err != nil {
+			return err
+		}
+	}
+
+	for !s.Ended() {
+		if _, err := s.WaitNextContext(ctx); err != nil {
+			return err
+		}
+
+		if err := p.send(enc, s); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// observe returns the stream to publish, and whether it was resumed at the
+// value the mirror already has.
+func (p *property[T]) observe(h hello) (observer.StreamOf[T], bool) {
+	if h.Resume {
+		if s, err := p.ObserveAt(h.Seq); err == nil {
+			return s, true
+		}
+	}
+
+	return p.Observe(), false
+}
+
+// send writes the current value of the given stream, or its end.
+func (p *property[T]) send(enc persist.Encoder, s observer.StreamOf[T]) error {
+	msg := message[T]{Seq: s.Seq()}
+
+	switch {
+	case s.Ended() && s.Err() != nil:
+		msg.End, msg.Err = true, s.Err().Error()
+	case s.Ended():
+		msg.End = true
+	default:
+		msg.Value = s.Value()
+	}
+
+	return enc.Encode(msg)
+}
@@ -0,0 +1,17 @@
+package rx
+
+import "time"
+
+// Clock tells the time to the operators that depend on it, such as Debounce and Timestamp. Operables use the system
+// clock unless another one is given with WithClock, e.g. a virtual clock to test time-based operators without sleeps.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+}
+
+// systemClock is the Clock returning the system time.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
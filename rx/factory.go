@@ -20,6 +20,7 @@ func MakeOperable(ctx context.Context, input observer.Stream, opts ...Option) *O
 		input: input.Clone(),
 
 		done:      make(chan struct{}),
+		dispose:   make(chan struct{}),
 		output:    p.Observe(),
 		operators: make([]operator, 0),
 		surrogate: p,
@@ -27,12 +28,15 @@ func MakeOperable(ctx context.Context, input observer.Stream, opts ...Option) *O
 
 	options := &options{
 		startStrategy: Lazy,
+		clock:         systemClock{},
 	}
 
 	for _, o := range opts {
 		o.apply(options)
 	}
 
+	o.clock = options.clock
+
 	if options.startStrategy == Eager {
 		o.Start()
 	}
@@ -40,7 +44,8 @@ func MakeOperable(ctx context.Context, input observer.Stream, opts ...Option) *O
 	return o
 }
 
-// Concat emit the emissions from two or more source streams without interleaving them.
+// Concat emit the emissions from two or more source streams without interleaving them. If any of the sources fails,
+// the resulting Operable fails with the same error.
 func Concat(ctx context.Context, sources []observer.Stream, opts ...Option) *Operable {
 	p := observer.NewProperty(nil)
 	s := p.Observe()
@@ -66,6 +71,12 @@ func Concat(ctx context.Context, sources []observer.Stream, opts ...Option) *Ope
 				case <-src.Changes():
 					v := src.Next()
 					if v == io.EOF {
+						if err := src.Err(); err != nil {
+							p.Fail(err)
+
+							return
+						}
+
 						break loop
 					}
 
@@ -79,3 +90,38 @@ func Concat(ctx context.Context, sources []observer.Stream, opts ...Option) *Ope
 
 	return MakeOperable(ctx, s, opts...)
 }
+
+// FromChannel makes the items received from the given channel operable. The resulting Operable ends when the channel
+// is closed, or fails with the context error if the context ends first.
+func FromChannel[T any](ctx context.Context, ch <-chan T, opts ...Option) *OperableOf[T] {
+	var zero T
+	p := observer.NewPropertyOf(zero)
+	s := p.Observe()
+	ready := make(chan struct{})
+
+	done := ctx.Done()
+	go func() {
+		close(ready)
+
+		for {
+			select {
+			case <-done:
+				p.Fail(ctx.Err())
+
+				return
+			case v, ok := <-ch:
+				if !ok {
+					p.End()
+
+					return
+				}
+
+				p.Update(v)
+			}
+		}
+	}()
+
+	<-ready
+
+	return MakeOperableOf(ctx, s, opts...)
+}
@@ -2,9 +2,11 @@ package rx_test
 
 import (
 	"context"
+	"errors"
 	"io"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/botchris/go-observer"
 	"github.com/botchris/go-observer/rx"
@@ -65,3 +67,63 @@ func TestFactory_Concat(t *testing.T) {
 		prev = v
 	}
 }
+
+func TestFactory_ConcatFailure(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	failure := errors.New("failure")
+	p1 := observer.NewProperty(nil)
+	p2 := observer.NewProperty(nil)
+
+	stream := rx.Concat(ctx, []observer.Stream{p1.Observe(), p2.Observe()})
+
+	p1.Update(1, 2)
+	p1.Fail(failure)
+	p2.Update(3)
+	p2.End()
+
+	require.Equal(t, []interface{}{1, 2}, stream.ToSlice())
+	require.Equal(t, failure, stream.Err())
+}
+
+func TestFactory_FromChannel(t *testing.T) {
+	t.Run("GIVEN a channel WHEN it is closed THEN the operable emits every item and ends", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		ch := make(chan int)
+		operable := rx.Filter(rx.FromChannel(ctx, ch), func(_ context.Context, v int) bool {
+			return v%2 == 0
+		})
+
+		go func() {
+			defer close(ch)
+
+			for i := 1; i <= 10; i++ {
+				ch <- i
+			}
+		}()
+
+		require.Equal(t, []int{2, 4, 6, 8, 10}, rx.ToSlice(operable))
+		require.NoError(t, operable.Err())
+	})
+
+	t.Run("GIVEN a channel that is never closed WHEN context ends THEN the operable fails with the context error", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		ch := make(chan string, 1)
+		ch <- "a"
+
+		operable := rx.FromChannel(ctx, ch)
+
+		read := make([]string, 0)
+		for v := range operable.Values(context.Background()) {
+			read = append(read, v)
+		}
+
+		require.Equal(t, []string{"a"}, read)
+		require.Equal(t, context.DeadlineExceeded, operable.Err())
+	})
+}
@@ -2,24 +2,36 @@ package rx
 
 import (
 	"context"
+	"errors"
 	"io"
+	"iter"
 	"sync"
 
 	"github.com/botchris/go-observer"
 )
 
+// ErrDisposed is reported by the output stream of an Operable that has been disposed, see Operable.Dispose.
+var ErrDisposed = errors.New("rx: operable disposed")
+
 // Operable defines a wrapped stream (input) on which operators can be applied to.
-// Operable Streams live as long as the underlying context remains active. If context ends or gets
-// cancelled operable will emit a io.EOF and no further items will be emitted.
+// Operable Streams live as long as the underlying context remains active, or until they are disposed. If context ends
+// or gets cancelled operable will emit a io.EOF and no further items will be emitted.
+//
+// When the input stream fails, the error is passed through to the output stream: operators are not given the chance
+// to emit anything else, the operable emits io.EOF and Err reports the input error.
 type Operable struct {
 	observer.Stream
 	ctx   context.Context
 	input observer.Stream
+	clock Clock
 
 	mu         sync.RWMutex
 	running    bool
 	completed  bool
+	disposed   bool
 	done       chan struct{}
+	dispose    chan struct{}
+	stopped    chan struct{}
 	output     observer.Stream
 	operators  []operator
 	surrogate  observer.Property
@@ -76,6 +88,7 @@ func (o *Operable) Start() *Operable {
 	}
 
 	ready := make(chan struct{})
+	o.stopped = make(chan struct{})
 	go o.run(ready)
 
 	<-ready
@@ -138,15 +151,194 @@ func (o *Operable) Next() interface{} {
 	o.Start()
 
 	value := o.output.Next()
-	if o.onNext != nil && value != io.EOF {
-		o.onNext(value)
-	}
+	o.emitted(value)
+
+	return value
+}
 
+// emitted runs the OnNext callback for the given item the stream advanced to,
+// or completes this Operable if it is io.EOF.
+func (o *Operable) emitted(value interface{}) {
 	if value == io.EOF {
 		o.complete()
+		return
 	}
 
-	return value
+	if o.onNext != nil {
+		o.onNext(value)
+	}
+}
+
+// HasNext checks whether there is a new value available.
+func (o *Operable) HasNext() bool {
+	o.Start()
+
+	return o.output.HasNext()
+}
+
+// Latest advances this stream straight to the newest item emitted, see observer.StreamOf.Latest. Skipped items are
+// not given to the OnNext callback.
+func (o *Operable) Latest() int {
+	o.Start()
+
+	seq := o.output.Seq()
+	skipped := o.output.Latest()
+
+	if o.output.Seq() != seq {
+		o.emitted(o.output.Value())
+	}
+
+	return skipped
+}
+
+// Drain advances this stream through every pending item without blocking, see observer.StreamOf.Drain. The OnNext
+// callback is called for every item drained.
+func (o *Operable) Drain(max int) []interface{} {
+	o.Start()
+
+	values := o.output.Drain(max)
+	for _, value := range values {
+		o.emitted(value)
+	}
+
+	if o.output.Ended() {
+		o.emitted(io.EOF)
+	}
+
+	return values
+}
+
+// WaitNext waits for Changes to be closed, advances the stream and returns the current value.
+func (o *Operable) WaitNext() interface{} {
+	<-o.Changes()
+
+	return o.Next()
+}
+
+// WaitNextContext is like WaitNext but gives up when ctx is done, in which case the stream is not advanced and the
+// context error is returned.
+func (o *Operable) WaitNextContext(ctx context.Context) (interface{}, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-o.Changes():
+		return o.Next(), nil
+	}
+}
+
+// WaitUntil advances the stream until its value satisfies the predicate, the current value is checked first. It
+// returns the matching value, or an error if ctx is done or the stream ends before any value matches: io.EOF when
+// the stream ended, or the error it failed with.
+func (o *Operable) WaitUntil(ctx context.Context, predicate func(interface{}) bool) (interface{}, error) {
+	value := o.Value()
+	for {
+		if o.Ended() {
+			if err := o.Err(); err != nil {
+				return value, err
+			}
+
+			return value, io.EOF
+		}
+
+		if predicate(value) {
+			return value, nil
+		}
+
+		var err error
+		if value, err = o.WaitNextContext(ctx); err != nil {
+			return value, err
+		}
+	}
+}
+
+// Ended reports whether this stream has reached io.EOF.
+func (o *Operable) Ended() bool {
+	o.Start()
+
+	return o.output.Ended()
+}
+
+// Err returns the error that made this stream end: the error the input stream failed with, or the context error if
+// the context ended before the input stream did. It returns nil while the stream has not ended, or if the input
+// stream ended without failing.
+func (o *Operable) Err() error {
+	o.Start()
+
+	return o.output.Err()
+}
+
+// Values returns an iterator over the items emitted by this stream from now on, advancing it as it goes. Iteration
+// stops when the stream ends or ctx is done. No goroutines are involved, so breaking out of the loop leaves nothing
+// behind.
+func (o *Operable) Values(ctx context.Context) iter.Seq[interface{}] {
+	return func(yield func(interface{}) bool) {
+		for {
+			value, err := o.WaitNextContext(ctx)
+			if err != nil || value == io.EOF || !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// Seq returns the sequence number of the current value of this stream. Operables number the items they emit on
+// their own, regardless of the sequence numbers of the input stream.
+func (o *Operable) Seq() uint64 {
+	o.Start()
+
+	return o.output.Seq()
+}
+
+// Lagged reports the values skipped by the last call to Next, see observer.StreamOf.Lagged.
+func (o *Operable) Lagged() *observer.Lagged {
+	o.Start()
+
+	return o.output.Lagged()
+}
+
+// Meta describes when the current value of this stream was emitted, see observer.StreamOf.Meta. Like sequence
+// numbers, the metadata of the input stream is not carried over to the items emitted.
+func (o *Operable) Meta() observer.Meta {
+	o.Start()
+
+	return o.output.Meta()
+}
+
+// Dispose detaches this Operable from its input stream without cancelling its context: it stops reading the input
+// stream and releases it, and the output stream ends failing with ErrDisposed. Unlike the Stream methods, Dispose can
+// be called from any goroutine, e.g. to unsubscribe an Operable that's being consumed elsewhere. It returns once the
+// Operable has stopped running, further calls are no-op.
+func (o *Operable) Dispose() {
+	o.mu.Lock()
+	if o.disposed {
+		o.mu.Unlock()
+		<-o.done
+
+		return
+	}
+
+	o.disposed = true
+	running := o.running
+	o.running = true
+	close(o.dispose)
+	o.mu.Unlock()
+
+	if running {
+		<-o.stopped
+		<-o.done
+
+		return
+	}
+
+	o.input.Close()
+	o.surrogate.Fail(ErrDisposed)
+	o.complete()
+}
+
+// Close disposes this Operable and closes its output stream, see Dispose and observer.StreamOf.Close.
+func (o *Operable) Close() {
+	o.Dispose()
+	o.output.Close()
 }
 
 // Done returns a channel that's closed when Operable stops running. A "done" operator will emit no further items.
@@ -204,9 +396,82 @@ func (o *Operable) ToMap(keySelector Mapper) map[interface{}]interface{} {
 	}
 }
 
+// ToChannel forwards every item emitted by this Operable to the returned channel, which is closed once the Operable
+// completes. The channel is buffered with the given size; when it is full items are handled according to the
+// WithOverflowStrategy option, by default the Operable waits for the receiver to make room.
+func (o *Operable) ToChannel(bufferSize int, opts ...Option) <-chan interface{} {
+	return toChannel(o, bufferSize, opts, func(v interface{}) interface{} {
+		return v
+	})
+}
+
+// toChannel implements ToChannel for both typed and untyped operables, items are converted before being sent.
+func toChannel[T any](o *Operable, bufferSize int, opts []Option, convert func(interface{}) T) <-chan T {
+	options := &options{
+		overflowStrategy: Block,
+	}
+
+	for _, opt := range opts {
+		opt.apply(options)
+	}
+
+	out := make(chan T, bufferSize)
+	ready := make(chan struct{})
+
+	go func() {
+		defer close(out)
+
+		close(ready)
+
+		done := o.ctx.Done()
+		for item := range o.Values(o.ctx) {
+			v := convert(item)
+
+			switch options.overflowStrategy {
+			case DropNewest:
+				select {
+				case out <- v:
+				default:
+				}
+			case DropOldest:
+				select {
+				case out <- v:
+					continue
+				default:
+				}
+
+				// make room by discarding the oldest buffered item, this goroutine is the only sender so the
+				// send below won't block unless the channel is unbuffered.
+				select {
+				case <-out:
+				default:
+				}
+
+				select {
+				case out <- v:
+				case <-done:
+					return
+				}
+			default:
+				select {
+				case out <- v:
+				case <-done:
+					return
+				}
+			}
+		}
+	}()
+
+	<-ready
+
+	return out
+}
+
 func (o *Operable) run(ready chan struct{}) {
 	defer func() {
+		o.input.Close()
 		o.surrogate.Update(io.EOF)
+		close(o.stopped)
 		o.complete()
 	}()
 
@@ -222,6 +487,12 @@ func (o *Operable) run(ready chan struct{}) {
 			o.mu.RUnlock()
 
 			if value == io.EOF {
+				if err := o.input.Err(); err != nil {
+					o.surrogate.Fail(err)
+
+					return
+				}
+
 				for _, operator := range operators {
 					dst := make(chan interface{}, 1)
 					operator.end(dst)
@@ -250,6 +521,12 @@ func (o *Operable) run(ready chan struct{}) {
 				o.surrogate.Update(value)
 			}
 		case <-done:
+			o.surrogate.Fail(o.ctx.Err())
+
+			return
+		case <-o.dispose:
+			o.surrogate.Fail(ErrDisposed)
+
 			return
 		}
 	}
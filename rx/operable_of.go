@@ -0,0 +1,429 @@
+package rx
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"iter"
+	"reflect"
+
+	"github.com/botchris/go-observer"
+)
+
+// OperableOf is the typed flavour of Operable: a wrapped stream of values of type T on which operators can be
+// applied to. Type-preserving and type-changing operators are exposed as free functions (e.g. Filter, Map), so the
+// compiler keeps track of the type of the items flowing through the pipeline.
+//
+// OperableOf is backed by an Operable, so the same lifecycle rules apply. Like the untyped operators, the free
+// functions add an operator to the given operable and return it: handles of a previous type must not be used once a
+// type-changing operator (e.g. Map) has been applied, reading from them panics with a type error.
+type OperableOf[T any] struct {
+	op *Operable
+}
+
+// MakeOperableOf makes the given typed input Stream operable so operators can be applied to. This new operable
+// instance will be valid as long as the given context keeps active.
+//
+// See MakeOperable for a description of the available start strategies.
+func MakeOperableOf[T any](ctx context.Context, input observer.StreamOf[T], opts ...Option) *OperableOf[T] {
+	return &OperableOf[T]{
+		op: MakeOperable(ctx, &untypedStream[T]{input: input}, opts...),
+	}
+}
+
+// Untyped returns the Operable backing this typed operable.
+func (o *OperableOf[T]) Untyped() *Operable {
+	return o.op
+}
+
+// Start starts reading the input stream, it will no-op if already started.
+func (o *OperableOf[T]) Start() *OperableOf[T] {
+	o.op.Start()
+
+	return o
+}
+
+// OnStart registers a callback action that will be called once the Operable starts reading the input Stream.
+func (o *OperableOf[T]) OnStart(startFunc func()) *OperableOf[T] {
+	o.op.OnStart(startFunc)
+
+	return o
+}
+
+// OnComplete registers a callback action that will be called after Next is invoked and reaches the end of the
+// stream.
+func (o *OperableOf[T]) OnComplete(completedFunc func()) *OperableOf[T] {
+	o.op.OnComplete(completedFunc)
+
+	return o
+}
+
+// OnNext registers a callback action that will be called each time Next method is invoked.
+func (o *OperableOf[T]) OnNext(nextFunc func(T)) *OperableOf[T] {
+	o.op.OnNext(func(v interface{}) {
+		nextFunc(as[T](v))
+	})
+
+	return o
+}
+
+// Value returns the current value for this stream.
+func (o *OperableOf[T]) Value() T {
+	return as[T](o.op.Value())
+}
+
+// Changes returns the channel that is closed when a new value is available.
+func (o *OperableOf[T]) Changes() chan struct{} {
+	return o.op.Changes()
+}
+
+// Next advances this stream to the next state.
+// You should never call this unless Changes channel is closed.
+func (o *OperableOf[T]) Next() T {
+	return as[T](o.op.Next())
+}
+
+// Latest advances this stream straight to the newest item emitted, see Operable.Latest.
+func (o *OperableOf[T]) Latest() int {
+	return o.op.Latest()
+}
+
+// Drain advances this stream through every pending item without blocking, see Operable.Drain.
+func (o *OperableOf[T]) Drain(max int) []T {
+	return asSlice[T](o.op.Drain(max))
+}
+
+// HasNext checks whether there is a new value available.
+func (o *OperableOf[T]) HasNext() bool {
+	return o.op.HasNext()
+}
+
+// WaitNext waits for Changes to be closed, advances the stream and returns the current value.
+func (o *OperableOf[T]) WaitNext() T {
+	return as[T](o.op.WaitNext())
+}
+
+// WaitNextContext is like WaitNext but gives up when ctx is done, see Operable.WaitNextContext.
+func (o *OperableOf[T]) WaitNextContext(ctx context.Context) (T, error) {
+	v, err := o.op.WaitNextContext(ctx)
+
+	return as[T](v), err
+}
+
+// WaitUntil advances the stream until its value satisfies the predicate, see Operable.WaitUntil.
+func (o *OperableOf[T]) WaitUntil(ctx context.Context, predicate func(T) bool) (T, error) {
+	v, err := o.op.WaitUntil(ctx, func(v interface{}) bool {
+		return predicate(as[T](v))
+	})
+
+	return as[T](v), err
+}
+
+// Ended reports whether this stream has reached its end.
+func (o *OperableOf[T]) Ended() bool {
+	return o.op.Ended()
+}
+
+// Err returns the error that made this stream end, see Operable.Err.
+func (o *OperableOf[T]) Err() error {
+	return o.op.Err()
+}
+
+// Values returns an iterator over the items emitted by this stream from now on, see Operable.Values.
+func (o *OperableOf[T]) Values(ctx context.Context) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range o.op.Values(ctx) {
+			if !yield(as[T](v)) {
+				return
+			}
+		}
+	}
+}
+
+// Seq returns the sequence number of the current value of this stream, see Operable.Seq.
+func (o *OperableOf[T]) Seq() uint64 {
+	return o.op.Seq()
+}
+
+// Lagged reports the values skipped by the last call to Next, see observer.StreamOf.Lagged.
+func (o *OperableOf[T]) Lagged() *observer.Lagged {
+	return o.op.Lagged()
+}
+
+// Meta describes when the current value of this stream was emitted, see Operable.Meta.
+func (o *OperableOf[T]) Meta() observer.Meta {
+	return o.op.Meta()
+}
+
+// Dispose detaches this Operable from its input stream without cancelling its context, see Operable.Dispose.
+func (o *OperableOf[T]) Dispose() {
+	o.op.Dispose()
+}
+
+// Close disposes this Operable and closes its output stream, see Operable.Close.
+func (o *OperableOf[T]) Close() {
+	o.op.Close()
+}
+
+// Done returns a channel that's closed when Operable stops running. A "done" operator will emit no further items.
+func (o *OperableOf[T]) Done() <-chan struct{} {
+	return o.op.Done()
+}
+
+// Clone creates a copy of this stream
+func (o *OperableOf[T]) Clone() observer.StreamOf[T] {
+	return &typedStream[T]{input: o.op.Clone()}
+}
+
+// ToSlice collects every emitted item until the end of the stream is reached an returns an slice holding each
+// collected item.
+func ToSlice[T any](o *OperableOf[T]) []T {
+	items := o.op.ToSlice()
+	out := make([]T, len(items))
+
+	for i, item := range items {
+		out[i] = as[T](item)
+	}
+
+	return out
+}
+
+// ToMap convert the sequence of emitted items into a map keyed by a specified key function.
+func ToMap[T any, K comparable](o *OperableOf[T], keySelector MapperOf[T, K]) map[K]T {
+	items := o.op.ToMap(func(ctx context.Context, v interface{}) interface{} {
+		return keySelector(ctx, as[T](v))
+	})
+
+	out := make(map[K]T, len(items))
+	for k, v := range items {
+		out[as[K](k)] = as[T](v)
+	}
+
+	return out
+}
+
+// ToChannel forwards every item emitted by this Operable to the returned channel, see Operable.ToChannel.
+func (o *OperableOf[T]) ToChannel(bufferSize int, opts ...Option) <-chan T {
+	return toChannel(o.op, bufferSize, opts, as[T])
+}
+
+// as converts the given item into a T, nil items and the io.EOF marking the end of the stream are converted to the
+// zero value of T. It panics if the item is of any other type, e.g. when reading from a handle whose type has been
+// changed by an operator.
+func as[T any](item interface{}) T {
+	if v, ok := item.(T); ok {
+		return v
+	}
+
+	var zero T
+	if item != nil && item != io.EOF {
+		panic(fmt.Sprintf("rx: %T item read from an operable of %s", item, reflect.TypeFor[T]()))
+	}
+
+	return zero
+}
+
+// asSlice converts the given items into Ts, see as.
+func asSlice[T any](items []interface{}) []T {
+	if items == nil {
+		return nil
+	}
+
+	out := make([]T, len(items))
+	for i, item := range items {
+		out[i] = as[T](item)
+	}
+
+	return out
+}
+
+// untypedStream adapts a typed stream so it can be consumed as an untyped one, signaling its end with io.EOF.
+type untypedStream[T any] struct {
+	input observer.StreamOf[T]
+}
+
+func (s *untypedStream[T]) Value() interface{} {
+	return s.value(s.input.Value())
+}
+
+func (s *untypedStream[T]) Changes() chan struct{} {
+	return s.input.Changes()
+}
+
+func (s *untypedStream[T]) Next() interface{} {
+	return s.value(s.input.Next())
+}
+
+func (s *untypedStream[T]) Latest() int {
+	return s.input.Latest()
+}
+
+func (s *untypedStream[T]) Drain(max int) []interface{} {
+	values := s.input.Drain(max)
+	if values == nil {
+		return nil
+	}
+
+	out := make([]interface{}, len(values))
+	for i, v := range values {
+		out[i] = v
+	}
+
+	return out
+}
+
+func (s *untypedStream[T]) HasNext() bool {
+	return s.input.HasNext()
+}
+
+func (s *untypedStream[T]) WaitNext() interface{} {
+	return s.value(s.input.WaitNext())
+}
+
+func (s *untypedStream[T]) Clone() observer.Stream {
+	return &untypedStream[T]{input: s.input.Clone()}
+}
+
+func (s *untypedStream[T]) Ended() bool {
+	return s.input.Ended()
+}
+
+func (s *untypedStream[T]) Err() error {
+	return s.input.Err()
+}
+
+func (s *untypedStream[T]) WaitNextContext(ctx context.Context) (interface{}, error) {
+	v, err := s.input.WaitNextContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.value(v), nil
+}
+
+func (s *untypedStream[T]) WaitUntil(ctx context.Context, predicate func(interface{}) bool) (interface{}, error) {
+	v, err := s.input.WaitUntil(ctx, func(v T) bool {
+		return predicate(v)
+	})
+
+	return s.value(v), err
+}
+
+func (s *untypedStream[T]) Values(ctx context.Context) iter.Seq[interface{}] {
+	return func(yield func(interface{}) bool) {
+		for v := range s.input.Values(ctx) {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+func (s *untypedStream[T]) Seq() uint64 {
+	return s.input.Seq()
+}
+
+func (s *untypedStream[T]) Lagged() *observer.Lagged {
+	return s.input.Lagged()
+}
+
+func (s *untypedStream[T]) Meta() observer.Meta {
+	return s.input.Meta()
+}
+
+func (s *untypedStream[T]) Close() {
+	s.input.Close()
+}
+
+func (s *untypedStream[T]) value(v T) interface{} {
+	if s.input.Ended() {
+		return io.EOF
+	}
+
+	return v
+}
+
+// typedStream adapts an untyped stream whose items are known to be of type T.
+type typedStream[T any] struct {
+	input observer.Stream
+}
+
+func (s *typedStream[T]) Value() T {
+	return as[T](s.input.Value())
+}
+
+func (s *typedStream[T]) Changes() chan struct{} {
+	return s.input.Changes()
+}
+
+func (s *typedStream[T]) Next() T {
+	return as[T](s.input.Next())
+}
+
+func (s *typedStream[T]) Latest() int {
+	return s.input.Latest()
+}
+
+func (s *typedStream[T]) Drain(max int) []T {
+	return asSlice[T](s.input.Drain(max))
+}
+
+func (s *typedStream[T]) HasNext() bool {
+	return s.input.HasNext()
+}
+
+func (s *typedStream[T]) WaitNext() T {
+	return as[T](s.input.WaitNext())
+}
+
+func (s *typedStream[T]) Clone() observer.StreamOf[T] {
+	return &typedStream[T]{input: s.input.Clone()}
+}
+
+func (s *typedStream[T]) Ended() bool {
+	return s.input.Ended()
+}
+
+func (s *typedStream[T]) Err() error {
+	return s.input.Err()
+}
+
+func (s *typedStream[T]) WaitNextContext(ctx context.Context) (T, error) {
+	v, err := s.input.WaitNextContext(ctx)
+
+	return as[T](v), err
+}
+
+func (s *typedStream[T]) WaitUntil(ctx context.Context, predicate func(T) bool) (T, error) {
+	v, err := s.input.WaitUntil(ctx, func(v interface{}) bool {
+		return predicate(as[T](v))
+	})
+
+	return as[T](v), err
+}
+
+func (s *typedStream[T]) Values(ctx context.Context) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range s.input.Values(ctx) {
+			if !yield(as[T](v)) {
+				return
+			}
+		}
+	}
+}
+
+func (s *typedStream[T]) Seq() uint64 {
+	return s.input.Seq()
+}
+
+func (s *typedStream[T]) Lagged() *observer.Lagged {
+	return s.input.Lagged()
+}
+
+func (s *typedStream[T]) Meta() observer.Meta {
+	return s.input.Meta()
+}
+
+func (s *typedStream[T]) Close() {
+	s.input.Close()
+}
@@ -0,0 +1,99 @@
+package rx_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/botchris/go-observer"
+	"github.com/botchris/go-observer/rx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperableOf(t *testing.T) {
+	t.Run("GIVEN a typed operable WHEN reading it as a stream THEN typed values are received until the end", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		prop := observer.NewPropertyOf(0)
+		stream := rx.MakeOperableOf(ctx, prop.Observe())
+
+		prop.Update(1, 2)
+		prop.End()
+
+		var stream2 observer.StreamOf[int] = stream.Clone()
+
+		require.Equal(t, 1, stream.WaitNext())
+		require.Equal(t, 2, stream.WaitNext())
+		require.False(t, stream.Ended())
+		require.Equal(t, 0, stream.WaitNext())
+		require.True(t, stream.Ended())
+
+		require.Equal(t, 1, stream2.WaitNext())
+	})
+
+	t.Run("GIVEN a chain of typed operators WHEN changing types THEN each stage receives typed items", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		type user struct {
+			name string
+			age  int
+		}
+
+		prop := observer.NewPropertyOf(user{})
+		adults := rx.Filter(rx.MakeOperableOf(ctx, prop.Observe()), func(_ context.Context, u user) bool {
+			return u.age >= 18
+		})
+
+		nexts := make([]string, 0)
+		names := rx.Map(adults, func(_ context.Context, u user) string {
+			return u.name
+		}).OnNext(func(name string) {
+			nexts = append(nexts, name)
+		})
+
+		prop.Update(user{"a", 20}, user{"b", 10}, user{"c", 30})
+		prop.End()
+
+		require.Equal(t, []string{"a", "c"}, rx.ToSlice(names))
+		require.Equal(t, []string{"a", "c"}, nexts)
+	})
+
+	t.Run("GIVEN a typed operable WHEN converting to a map THEN items are keyed by the selector", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		prop := observer.NewPropertyOf("")
+		lengths := rx.Map(rx.MakeOperableOf(ctx, prop.Observe()), func(_ context.Context, s string) int {
+			return len(s)
+		})
+
+		prop.Update("a", "bb", "ccc")
+		prop.End()
+
+		results := rx.ToMap(lengths, func(_ context.Context, n int) string {
+			return string(rune('a' + n - 1))
+		})
+
+		require.Equal(t, map[string]int{"a": 1, "b": 2, "c": 3}, results)
+	})
+
+	t.Run("GIVEN a type-changing operator WHEN reading from the previous handle THEN it panics with a type error", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		prop := observer.NewPropertyOf(0)
+		ints := rx.MakeOperableOf(ctx, prop.Observe())
+		rx.Map(ints, func(_ context.Context, n int) string {
+			return strconv.Itoa(n)
+		})
+
+		prop.Update(1)
+
+		require.PanicsWithValue(t, "rx: string item read from an operable of int", func() {
+			ints.WaitNext()
+		})
+	})
+}
@@ -2,8 +2,10 @@ package rx_test
 
 import (
 	"context"
+	"errors"
 	"io"
 	"testing"
+	"time"
 
 	"github.com/botchris/go-observer"
 	"github.com/botchris/go-observer/rx"
@@ -97,3 +99,357 @@ func TestOperable_OnComplete(t *testing.T) {
 		require.EqualValues(t, 1, onCompleteCalls)
 	})
 }
+
+func TestOperable_Err(t *testing.T) {
+	t.Run("GIVEN an operable with an aggregation operator WHEN input fails THEN error is passed through and nothing is aggregated", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		failure := errors.New("failure")
+		prop := observer.NewProperty(nil)
+		operable := rx.MakeOperable(ctx, prop.Observe()).
+			Filter(func(_ context.Context, v interface{}) bool {
+				return v.(int) > 1
+			}).
+			Last()
+
+		prop.Update(1, 2, 3)
+		prop.Fail(failure)
+
+		require.Empty(t, operable.ToSlice())
+		require.True(t, operable.Ended())
+		require.Equal(t, failure, operable.Err())
+	})
+
+	t.Run("GIVEN an operable WHEN input ends THEN no error is reported", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		prop := observer.NewProperty(nil)
+		operable := rx.MakeOperable(ctx, prop.Observe()).Last()
+
+		prop.Update(1, 2, 3)
+		prop.End()
+
+		require.Equal(t, []interface{}{3}, operable.ToSlice())
+		require.NoError(t, operable.Err())
+	})
+
+	t.Run("GIVEN an operable WHEN context is cancelled THEN context error is reported", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		prop := observer.NewProperty(nil)
+		operable := rx.MakeOperable(ctx, prop.Observe())
+
+		require.Equal(t, io.EOF, operable.WaitNext())
+		require.Equal(t, context.Canceled, operable.Err())
+	})
+
+	t.Run("GIVEN a typed operable WHEN input fails THEN error is reported", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		failure := errors.New("failure")
+		prop := observer.NewPropertyOf(0)
+		operable := rx.Map(rx.MakeOperableOf(ctx, prop.Observe()), func(_ context.Context, v int) int {
+			return v * 2
+		})
+
+		prop.Update(1, 2)
+		prop.Fail(failure)
+
+		require.Equal(t, []int{2, 4}, rx.ToSlice(operable))
+		require.Equal(t, failure, operable.Err())
+	})
+}
+
+func TestOperable_WaitUntil(t *testing.T) {
+	t.Run("GIVEN a filtered operable WHEN waiting for a value THEN the first matching item is returned", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		prop := observer.NewPropertyOf(0)
+		operable := rx.Filter(rx.MakeOperableOf(ctx, prop.Observe()), func(_ context.Context, v int) bool {
+			return v%2 == 0
+		})
+
+		prop.Update(1, 2, 3, 4, 5)
+		prop.End()
+
+		v, err := operable.WaitUntil(ctx, func(v int) bool {
+			return v > 2
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, 4, v)
+
+		_, err = operable.WaitUntil(ctx, func(v int) bool {
+			return v > 4
+		})
+
+		require.Equal(t, io.EOF, err)
+	})
+
+	t.Run("GIVEN an idle operable WHEN waiting with a deadline THEN the deadline error is returned", func(t *testing.T) {
+		prop := observer.NewProperty(nil)
+		operable := rx.MakeOperable(context.Background(), prop.Observe())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err := operable.WaitNextContext(ctx)
+		require.Equal(t, context.DeadlineExceeded, err)
+	})
+}
+
+func TestOperable_Values(t *testing.T) {
+	t.Run("GIVEN a filtered operable WHEN ranging over its values THEN items are received until the end", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		prop := observer.NewProperty(nil)
+		operable := rx.MakeOperable(ctx, prop.Observe()).
+			Filter(func(_ context.Context, v interface{}) bool {
+				return v.(int)%2 == 0
+			})
+
+		prop.Update(1, 2, 3, 4)
+		prop.End()
+
+		read := make([]interface{}, 0)
+		for v := range operable.Values(ctx) {
+			read = append(read, v)
+		}
+
+		require.Equal(t, []interface{}{2, 4}, read)
+		require.True(t, operable.Ended())
+	})
+
+	t.Run("GIVEN a typed operable WHEN breaking out of the loop THEN the remaining items can still be read", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		prop := observer.NewPropertyOf("")
+		operable := rx.MakeOperableOf(ctx, prop.Observe())
+
+		prop.Update("a", "b", "c")
+		prop.End()
+
+		for v := range operable.Values(ctx) {
+			require.Equal(t, "a", v)
+
+			break
+		}
+
+		require.Equal(t, []string{"b", "c"}, rx.ToSlice(operable))
+	})
+}
+
+func TestOperable_ToChannel(t *testing.T) {
+	t.Run("GIVEN an operable WHEN forwarding to a channel THEN every item is received and the channel is closed", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		prop := observer.NewProperty(nil)
+		ch := rx.MakeOperable(ctx, prop.Observe()).ToChannel(0)
+
+		prop.Update(1, 2, 3)
+		prop.End()
+
+		read := make([]interface{}, 0)
+		for v := range ch {
+			read = append(read, v)
+		}
+
+		require.Equal(t, []interface{}{1, 2, 3}, read)
+	})
+
+	t.Run("GIVEN a full channel WHEN dropping newest THEN the first items are kept", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		prop := observer.NewPropertyOf(0)
+		ch := rx.MakeOperableOf(ctx, prop.Observe()).ToChannel(2, rx.WithOverflowStrategy(rx.DropNewest))
+
+		prop.Update(1, 2, 3, 4)
+		prop.End()
+
+		// give the operable some time to forward every item before reading
+		time.Sleep(100 * time.Millisecond)
+
+		read := make([]int, 0)
+		for v := range ch {
+			read = append(read, v)
+		}
+
+		require.Equal(t, []int{1, 2}, read)
+	})
+
+	t.Run("GIVEN a full channel WHEN dropping oldest THEN the last items are kept", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		prop := observer.NewPropertyOf(0)
+		ch := rx.MakeOperableOf(ctx, prop.Observe()).ToChannel(2, rx.WithOverflowStrategy(rx.DropOldest))
+
+		prop.Update(1, 2, 3, 4)
+		prop.End()
+
+		// give the operable some time to forward every item before reading
+		time.Sleep(100 * time.Millisecond)
+
+		read := make([]int, 0)
+		for v := range ch {
+			read = append(read, v)
+		}
+
+		require.Equal(t, []int{3, 4}, read)
+	})
+
+	t.Run("GIVEN a blocked receiver WHEN context ends THEN the channel is closed", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		prop := observer.NewProperty(nil)
+		ch := rx.MakeOperable(ctx, prop.Observe()).ToChannel(0)
+
+		prop.Update(1, 2, 3)
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+
+		read := 0
+		for range ch {
+			read++
+		}
+
+		require.LessOrEqual(t, read, 1)
+	})
+}
+
+func TestOperable_Dispose(t *testing.T) {
+	t.Run("GIVEN a running operable WHEN disposed THEN it stops reading the input without cancelling the context", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		prop := observer.NewProperty(nil)
+		input := prop.Observe()
+		defer input.Close()
+		operable := rx.MakeOperable(ctx, input)
+
+		prop.Update(1)
+		require.Equal(t, 1, operable.WaitNext())
+		require.Equal(t, 2, prop.Stats().Streams)
+
+		operable.Dispose()
+		operable.Dispose()
+
+		require.Equal(t, 1, prop.Stats().Streams)
+		require.Equal(t, io.EOF, operable.WaitNext())
+		require.Equal(t, rx.ErrDisposed, operable.Err())
+		require.NoError(t, ctx.Err())
+
+		select {
+		case <-operable.Done():
+		default:
+			require.Fail(t, "operable is still running")
+		}
+	})
+
+	t.Run("GIVEN an operable being consumed WHEN disposed from another goroutine THEN the consumer stops", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		prop := observer.NewPropertyOf(0)
+		operable := rx.MakeOperableOf(ctx, prop.Observe())
+		values := operable.ToChannel(0)
+
+		prop.Update(1)
+		require.Equal(t, 1, <-values)
+
+		operable.Dispose()
+
+		_, ok := <-values
+		require.False(t, ok)
+		require.Equal(t, rx.ErrDisposed, operable.Err())
+	})
+
+	t.Run("GIVEN a lazy operable WHEN disposed before starting THEN the input is released and it never starts", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		started := false
+		prop := observer.NewProperty(nil)
+		input := prop.Observe()
+		defer input.Close()
+		operable := rx.MakeOperable(ctx, input).OnStart(func() {
+			started = true
+		})
+
+		operable.Dispose()
+		prop.Update(1)
+
+		require.Equal(t, 1, prop.Stats().Streams)
+		require.Empty(t, operable.ToSlice())
+		require.Equal(t, rx.ErrDisposed, operable.Err())
+		require.False(t, started)
+	})
+
+	t.Run("GIVEN an operable WHEN closed THEN its output stream is closed as well", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		prop := observer.NewProperty(nil)
+		input := prop.Observe()
+		defer input.Close()
+		operable := rx.MakeOperable(ctx, input, rx.WithStartStrategy(rx.Eager))
+
+		operable.Close()
+
+		require.Equal(t, 1, prop.Stats().Streams)
+		require.True(t, operable.Ended())
+		require.Equal(t, observer.ErrClosed, operable.Err())
+	})
+}
+
+func TestOperable_Drain(t *testing.T) {
+	t.Run("GIVEN an operable WHEN draining THEN callbacks are invoked for every item", func(t *testing.T) {
+		prop := observer.NewProperty(0)
+		nextCalls, completeCalls := 0, 0
+		operable := rx.MakeOperable(context.Background(), prop.Observe()).
+			OnNext(func(interface{}) {
+				nextCalls++
+			}).
+			OnComplete(func() {
+				completeCalls++
+			})
+
+		prop.Update(1, 2, 3)
+		prop.End()
+
+		values := make([]interface{}, 0)
+		require.Eventually(t, func() bool {
+			values = append(values, operable.Drain(0)...)
+			return operable.Ended()
+		}, time.Second, time.Millisecond)
+
+		require.Equal(t, []interface{}{1, 2, 3}, values)
+		require.Equal(t, 3, nextCalls)
+		require.Equal(t, 1, completeCalls)
+	})
+
+	t.Run("GIVEN a typed operable WHEN skipping to the latest item THEN the items in between are skipped", func(t *testing.T) {
+		prop := observer.NewPropertyOf(0)
+		operable := rx.MakeOperableOf(context.Background(), prop.Observe())
+		prop.Update(1, 2, 3)
+
+		require.Eventually(t, func() bool {
+			operable.Latest()
+			return operable.Value() == 3
+		}, time.Second, time.Millisecond)
+
+		prop.Update(4, 5)
+		require.Eventually(t, func() bool {
+			return len(operable.Drain(1)) == 0 && operable.Value() == 5
+		}, time.Second, time.Millisecond)
+	})
+}
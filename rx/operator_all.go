@@ -35,3 +35,12 @@ func (o *Operable) All(predicate Predicate) *Operable {
 
 	return o
 }
+
+// All determine whether all items emitted meet some criteria.
+func All[T any](o *OperableOf[T], predicate PredicateOf[T]) *OperableOf[bool] {
+	o.op.All(func(ctx context.Context, v interface{}) bool {
+		return predicate(ctx, as[T](v))
+	})
+
+	return &OperableOf[bool]{op: o.op}
+}
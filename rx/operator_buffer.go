@@ -1,5 +1,7 @@
 package rx
 
+import "context"
+
 type operatorBufferWithCount struct {
 	size   int
 	count  int
@@ -40,3 +42,20 @@ func (o *Operable) BufferWithCount(size int) *Operable {
 
 	return o
 }
+
+// BufferWithCount periodically gather items emitted into bundles and emit these bundles rather than emitting the items one at a time.
+func BufferWithCount[T any](o *OperableOf[T], size int) *OperableOf[[]T] {
+	o.op.BufferWithCount(size)
+	o.op.Map(func(_ context.Context, v interface{}) interface{} {
+		items := v.([]interface{})
+		out := make([]T, len(items))
+
+		for i, item := range items {
+			out[i] = as[T](item)
+		}
+
+		return out
+	})
+
+	return &OperableOf[[]T]{op: o.op}
+}
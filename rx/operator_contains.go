@@ -41,3 +41,12 @@ func (o *Operable) Contains(predicate Predicate) *Operable {
 
 	return o
 }
+
+// Contains determine whether a particular item was emitted or not.
+func Contains[T any](o *OperableOf[T], predicate PredicateOf[T]) *OperableOf[bool] {
+	o.op.Contains(func(ctx context.Context, v interface{}) bool {
+		return predicate(ctx, as[T](v))
+	})
+
+	return &OperableOf[bool]{op: o.op}
+}
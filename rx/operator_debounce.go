@@ -3,12 +3,13 @@ package rx
 import "time"
 
 type operatorDebounce struct {
+	clock    Clock
 	last     time.Time
 	timespan time.Duration
 }
 
 func (o *operatorDebounce) next(item interface{}, dst chan<- interface{}) bool {
-	now := time.Now()
+	now := o.clock.Now()
 	if now.After(o.last.Add(o.timespan)) {
 		o.last = now
 		send(dst, item)
@@ -27,9 +28,17 @@ func (o *Operable) Debounce(timespan time.Duration) *Operable {
 	defer o.mu.Unlock()
 
 	o.operators = append(o.operators, &operatorDebounce{
-		last:     time.Now(),
+		clock:    o.clock,
+		last:     o.clock.Now(),
 		timespan: timespan,
 	})
 
 	return o
 }
+
+// Debounce only emit an item if a particular timespan has passed without it emitting another item.
+func Debounce[T any](o *OperableOf[T], timespan time.Duration) *OperableOf[T] {
+	o.op.Debounce(timespan)
+
+	return o
+}
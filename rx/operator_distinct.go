@@ -38,3 +38,12 @@ func (o *Operable) Distinct(apply Mapper) *Operable {
 
 	return o
 }
+
+// Distinct suppresses duplicate items, two items are considered duplicates if they produce the same key.
+func Distinct[T any, K comparable](o *OperableOf[T], key MapperOf[T, K]) *OperableOf[T] {
+	o.op.Distinct(func(ctx context.Context, v interface{}) interface{} {
+		return key(ctx, as[T](v))
+	})
+
+	return o
+}
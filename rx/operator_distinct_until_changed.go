@@ -37,3 +37,13 @@ func (o *Operable) DistinctUntilChanged(apply Mapper) *Operable {
 
 	return o
 }
+
+// DistinctUntilChanged suppresses consecutive duplicate items, two items are considered duplicates if they produce
+// the same key.
+func DistinctUntilChanged[T any, K comparable](o *OperableOf[T], key MapperOf[T, K]) *OperableOf[T] {
+	o.op.DistinctUntilChanged(func(ctx context.Context, v interface{}) interface{} {
+		return key(ctx, as[T](v))
+	})
+
+	return o
+}
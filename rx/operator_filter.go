@@ -33,3 +33,12 @@ func (o *Operable) Filter(predicate Predicate) *Operable {
 
 	return o
 }
+
+// Filter emit only those items that pass a predicate test.
+func Filter[T any](o *OperableOf[T], predicate PredicateOf[T]) *OperableOf[T] {
+	o.op.Filter(func(ctx context.Context, v interface{}) bool {
+		return predicate(ctx, as[T](v))
+	})
+
+	return o
+}
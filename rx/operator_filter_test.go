@@ -104,3 +104,22 @@ func TestOperable_Filter(t *testing.T) {
 		}
 	})
 }
+
+func TestOperableOf_Filter(t *testing.T) {
+	t.Run("GIVEN a typed stream of integers WHEN filtering evens THEN only evens are received", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		prop := observer.NewPropertyOf(0)
+		stream := rx.Filter(rx.MakeOperableOf(ctx, prop.Observe()), func(_ context.Context, v int) bool {
+			return v%2 == 0
+		})
+
+		for i := 1; i <= 10; i++ {
+			prop.Update(i)
+		}
+		prop.End()
+
+		require.Equal(t, []int{2, 4, 6, 8, 10}, rx.ToSlice(stream))
+	})
+}
@@ -12,22 +12,23 @@ func (o *Operable) GroupBy(length int, distribution func(item interface{}) int)
 	defer o.mu.Unlock()
 
 	root := observer.NewProperty(nil)
-	fork := MakeOperable(o.ctx, root.Observe())
+	fork := MakeOperable(o.ctx, root.Observe(), WithClock(o.clock))
 	properties := make([]observer.Property, length)
 
 	for i := 0; i < length; i++ {
 		p := observer.NewProperty(nil)
 		properties[i] = p
-		root.Update(MakeOperable(o.ctx, p.Observe()))
+		root.Update(MakeOperable(o.ctx, p.Observe(), WithClock(o.clock)))
 	}
 
 	root.End()
 	ready := make(chan struct{})
 	done := o.ctx.Done()
 	go func() {
+		var err error
 		defer func() {
 			for i := 0; i < length; i++ {
-				properties[i].End()
+				properties[i].Fail(err)
 			}
 		}()
 
@@ -40,6 +41,8 @@ func (o *Operable) GroupBy(length int, distribution func(item interface{}) int)
 			case <-o.Changes():
 				value := o.Next()
 				if value == io.EOF {
+					err = o.Err()
+
 					return
 				}
 
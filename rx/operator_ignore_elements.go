@@ -17,3 +17,10 @@ func (o *Operable) IgnoreElements() *Operable {
 
 	return o
 }
+
+// IgnoreElements do not emit any items but mirror its termination notification.
+func IgnoreElements[T any](o *OperableOf[T]) *OperableOf[T] {
+	o.op.IgnoreElements()
+
+	return o
+}
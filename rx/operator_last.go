@@ -29,3 +29,10 @@ func (o *Operable) Last() *Operable {
 
 	return o
 }
+
+// Last emit only the last item.
+func Last[T any](o *OperableOf[T]) *OperableOf[T] {
+	o.op.Last()
+
+	return o
+}
@@ -34,3 +34,10 @@ func (o *Operable) LastOrDefault(defaultValue interface{}) *Operable {
 
 	return o
 }
+
+// LastOrDefault emit only the last item. If fails to emit any items, it emits a default value.
+func LastOrDefault[T any](o *OperableOf[T], defaultValue T) *OperableOf[T] {
+	o.op.LastOrDefault(defaultValue)
+
+	return o
+}
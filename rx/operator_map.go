@@ -29,3 +29,12 @@ func (o *Operable) Map(mapper Mapper) *Operable {
 
 	return o
 }
+
+// Map transform the items by applying a function to each item.
+func Map[T any, U any](o *OperableOf[T], mapper MapperOf[T, U]) *OperableOf[U] {
+	o.op.Map(func(ctx context.Context, v interface{}) interface{} {
+		return mapper(ctx, as[T](v))
+	})
+
+	return &OperableOf[U]{op: o.op}
+}
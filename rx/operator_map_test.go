@@ -3,7 +3,9 @@ package rx_test
 import (
 	"context"
 	"math"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/botchris/go-observer"
 	"github.com/botchris/go-observer/rx"
@@ -45,3 +47,21 @@ func TestOperable_Map(t *testing.T) {
 		}
 	})
 }
+
+func TestOperableOf_Map(t *testing.T) {
+	t.Run("GIVEN a typed stream of integers WHEN mapping to strings THEN typed strings are received", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		prop := observer.NewPropertyOf(0)
+		operable := rx.MakeOperableOf(ctx, prop.Observe())
+		stream := rx.Map(operable, func(_ context.Context, v int) string {
+			return strconv.Itoa(v * 10)
+		})
+
+		prop.Update(1, 2, 3)
+		prop.End()
+
+		require.Equal(t, []string{"10", "20", "30"}, rx.ToSlice(stream))
+	})
+}
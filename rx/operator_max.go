@@ -1,6 +1,7 @@
 package rx
 
 import (
+	"cmp"
 	"context"
 )
 
@@ -45,3 +46,19 @@ func (o *Operable) Max(comparator Comparator) *Operable {
 
 	return o
 }
+
+// Max determines and emits the maximum-valued item.
+func Max[T cmp.Ordered](o *OperableOf[T]) *OperableOf[T] {
+	return MaxFunc(o, func(_ context.Context, a T, b T) int {
+		return cmp.Compare(a, b)
+	})
+}
+
+// MaxFunc determines and emits the maximum-valued item according to a comparator.
+func MaxFunc[T any](o *OperableOf[T], comparator ComparatorOf[T]) *OperableOf[T] {
+	o.op.Max(func(ctx context.Context, a interface{}, b interface{}) int {
+		return comparator(ctx, as[T](a), as[T](b))
+	})
+
+	return o
+}
@@ -28,3 +28,33 @@ func TestOperable_Max(t *testing.T) {
 	require.Len(t, items, 1)
 	require.EqualValues(t, 6, items[0].(int))
 }
+
+func TestOperableOf_Max(t *testing.T) {
+	t.Run("GIVEN a typed stream of integers WHEN applying max THEN the maximum is emitted", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		prop := observer.NewPropertyOf(0)
+		stream := rx.Max(rx.MakeOperableOf(ctx, prop.Observe()))
+
+		prop.Update(2, 5, 1, 6, 3, 4)
+		prop.End()
+
+		require.Equal(t, []int{6}, rx.ToSlice(stream))
+	})
+
+	t.Run("GIVEN a typed stream of strings WHEN applying max with a comparator THEN the longest is emitted", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		prop := observer.NewPropertyOf("")
+		stream := rx.MaxFunc(rx.MakeOperableOf(ctx, prop.Observe()), func(_ context.Context, a string, b string) int {
+			return len(a) - len(b)
+		})
+
+		prop.Update("aa", "aaaa", "a", "aaa")
+		prop.End()
+
+		require.Equal(t, []string{"aaaa"}, rx.ToSlice(stream))
+	})
+}
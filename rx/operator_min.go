@@ -1,6 +1,7 @@
 package rx
 
 import (
+	"cmp"
 	"context"
 )
 
@@ -45,3 +46,19 @@ func (o *Operable) Min(comparator Comparator) *Operable {
 
 	return o
 }
+
+// Min determines and emits the minimum-valued item.
+func Min[T cmp.Ordered](o *OperableOf[T]) *OperableOf[T] {
+	return MinFunc(o, func(_ context.Context, a T, b T) int {
+		return cmp.Compare(a, b)
+	})
+}
+
+// MinFunc determines and emits the minimum-valued item according to a comparator.
+func MinFunc[T any](o *OperableOf[T], comparator ComparatorOf[T]) *OperableOf[T] {
+	o.op.Min(func(ctx context.Context, a interface{}, b interface{}) int {
+		return comparator(ctx, as[T](a), as[T](b))
+	})
+
+	return o
+}
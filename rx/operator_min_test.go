@@ -28,3 +28,16 @@ func TestOperable_Min(t *testing.T) {
 	require.Len(t, items, 1)
 	require.EqualValues(t, 1, items[0].(int))
 }
+
+func TestOperableOf_Min(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	prop := observer.NewPropertyOf(0.0)
+	stream := rx.Min(rx.MakeOperableOf(ctx, prop.Observe()))
+
+	prop.Update(2.5, 5, 1.5, 6, 3, 4)
+	prop.End()
+
+	require.Equal(t, []float64{1.5}, rx.ToSlice(stream))
+}
@@ -42,3 +42,12 @@ func (o *Operable) SkipWhile(predicate Predicate) *Operable {
 
 	return o
 }
+
+// SkipWhile discard items until a specified condition becomes false.
+func SkipWhile[T any](o *OperableOf[T], predicate PredicateOf[T]) *OperableOf[T] {
+	o.op.SkipWhile(func(ctx context.Context, v interface{}) bool {
+		return predicate(ctx, as[T](v))
+	})
+
+	return o
+}
@@ -1,20 +1,26 @@
 package rx
 
 import (
+	"context"
 	"time"
 )
 
-type operatorTimestamp struct{}
+type operatorTimestamp struct {
+	clock Clock
+}
 
-// TimestampItem attach a timestamp to an item.
-type TimestampItem struct {
+// TimestampItemOf attach a timestamp to an item of type T.
+type TimestampItemOf[T any] struct {
 	Timestamp time.Time
-	Item      interface{}
+	Item      T
 }
 
+// TimestampItem attach a timestamp to an item.
+type TimestampItem = TimestampItemOf[interface{}]
+
 func (o *operatorTimestamp) next(item interface{}, dst chan<- interface{}) bool {
 	send(dst, TimestampItem{
-		Timestamp: time.Now().UTC(),
+		Timestamp: o.clock.Now().UTC(),
 		Item:      item,
 	})
 
@@ -28,7 +34,22 @@ func (o *Operable) Timestamp() *Operable {
 	o.mu.Lock()
 	defer o.mu.Unlock()
 
-	o.operators = append(o.operators, &operatorTimestamp{})
+	o.operators = append(o.operators, &operatorTimestamp{clock: o.clock})
 
 	return o
 }
+
+// Timestamp attaches a timestamp to each item indicating when it was emitted.
+func Timestamp[T any](o *OperableOf[T]) *OperableOf[TimestampItemOf[T]] {
+	o.op.Timestamp()
+	o.op.Map(func(_ context.Context, v interface{}) interface{} {
+		ts := v.(TimestampItem)
+
+		return TimestampItemOf[T]{
+			Timestamp: ts.Timestamp,
+			Item:      as[T](ts.Item),
+		}
+	})
+
+	return &OperableOf[TimestampItemOf[T]]{op: o.op}
+}
@@ -6,7 +6,9 @@ type Option interface {
 }
 
 type options struct {
-	startStrategy startStrategy
+	startStrategy    startStrategy
+	overflowStrategy overflowStrategy
+	clock            Clock
 }
 
 type funcOption struct {
@@ -25,3 +27,21 @@ func WithStartStrategy(m startStrategy) Option {
 		},
 	}
 }
+
+// WithClock sets the Clock used by the time-based operators of the Operable, defaults to the system clock.
+func WithClock(c Clock) Option {
+	return &funcOption{
+		fn: func(o *options) {
+			o.clock = c
+		},
+	}
+}
+
+// WithOverflowStrategy sets how ToChannel behaves when the returned channel is full, defaults to Block.
+func WithOverflowStrategy(s overflowStrategy) Option {
+	return &funcOption{
+		fn: func(o *options) {
+			o.overflowStrategy = s
+		},
+	}
+}
@@ -0,0 +1,152 @@
+package rxtest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Values maps the characters of a marble diagram to the values they stand for.
+// Characters missing from the map stand for themselves, as a string.
+type Values map[string]interface{}
+
+// event is a notification of a marble diagram: a value, the end of the stream,
+// or its failure, at a given frame.
+type event struct {
+	Frame int
+	Value interface{}
+	End   bool
+	Err   error
+}
+
+// parse turns the given marble diagram into the events it describes, '#'
+// standing for a failure with the given error.
+func parse(marble string, values Values, failure error) ([]event, error) {
+	events := make([]event, 0)
+	frame, group, inside := 0, false, 0
+	ended := false
+
+	for i, c := range marble {
+		if c == ' ' {
+			continue
+		}
+
+		if ended && (c != ')' || !group) {
+			return nil, fmt.Errorf("rxtest: unexpected %q after the end of %q", c, marble)
+		}
+
+		switch c {
+		case '-':
+			if group {
+				return nil, fmt.Errorf("rxtest: unexpected '-' in a group of %q at %d", marble, i)
+			}
+
+			frame++
+
+			continue
+		case '(':
+			if group {
+				return nil, fmt.Errorf("rxtest: nested group in %q at %d", marble, i)
+			}
+
+			group, inside = true, 0
+
+			continue
+		case ')':
+			if !group {
+				return nil, fmt.Errorf("rxtest: unexpected ')' in %q at %d", marble, i)
+			}
+
+			group = false
+			frame += inside + 2
+
+			continue
+		case '|':
+			events = append(events, event{Frame: frame, End: true})
+			ended = true
+		case '#':
+			events = append(events, event{Frame: frame, End: true, Err: failure})
+			ended = true
+		default:
+			events = append(events, event{Frame: frame, Value: lookup(values, string(c))})
+		}
+
+		if group {
+			inside++
+		} else {
+			frame++
+		}
+	}
+
+	if group {
+		return nil, fmt.Errorf("rxtest: unclosed group in %q", marble)
+	}
+
+	return events, nil
+}
+
+// lookup returns the value the given character stands for.
+func lookup(values Values, c string) interface{} {
+	if v, ok := values[c]; ok {
+		return v
+	}
+
+	return c
+}
+
+// render draws the given events as a marble diagram, values are drawn with
+// the character they stand for, or '?' if there is none.
+func render(events []event, values Values) string {
+	var b strings.Builder
+
+	frame := 0
+	for i := 0; i < len(events); {
+		j := i + 1
+		for j < len(events) && events[j].Frame == events[i].Frame {
+			j++
+		}
+
+		b.WriteString(strings.Repeat("-", events[i].Frame-frame))
+
+		if j-i > 1 {
+			b.WriteByte('(')
+		}
+
+		for _, e := range events[i:j] {
+			b.WriteString(symbol(e, values))
+		}
+
+		if j-i > 1 {
+			b.WriteByte(')')
+			frame = events[i].Frame + j - i + 2
+		} else {
+			frame = events[i].Frame + 1
+		}
+
+		i = j
+	}
+
+	return b.String()
+}
+
+// symbol returns the character drawing the given event.
+func symbol(e event, values Values) string {
+	switch {
+	case e.End && e.Err != nil:
+		return "#"
+	case e.End:
+		return "|"
+	}
+
+	for c, v := range values {
+		if reflect.DeepEqual(v, e.Value) {
+			return c
+		}
+	}
+
+	if s, ok := e.Value.(string); ok && len(s) == 1 {
+		return s
+	}
+
+	return "?"
+}
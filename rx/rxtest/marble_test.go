@@ -0,0 +1,39 @@
+package rxtest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	failure := errors.New("failure")
+
+	t.Run("GIVEN a marble diagram WHEN parsed THEN its events are placed at their frames", func(t *testing.T) {
+		events, err := parse("-a-(bc)-d #", Values{"a": 1}, failure)
+		require.NoError(t, err)
+		require.Equal(t, []event{
+			{Frame: 1, Value: 1},
+			{Frame: 3, Value: "b"},
+			{Frame: 3, Value: "c"},
+			{Frame: 8, Value: "d"},
+			{Frame: 9, End: true, Err: failure},
+		}, events)
+	})
+
+	t.Run("GIVEN malformed marble diagrams WHEN parsed THEN an error is returned", func(t *testing.T) {
+		for _, marble := range []string{"-a|-", "(a(b))", "a)", "(a", "(a-b)"} {
+			_, err := parse(marble, nil, failure)
+			require.Error(t, err, marble)
+		}
+	})
+
+	t.Run("GIVEN events WHEN rendered THEN the marble diagram is drawn", func(t *testing.T) {
+		for _, marble := range []string{"-a-(bc)-d#", "--x--|", "(a|)", ""} {
+			events, err := parse(marble, Values{"x": 42}, failure)
+			require.NoError(t, err)
+			require.Equal(t, marble, render(events, Values{"x": 42}))
+		}
+	})
+}
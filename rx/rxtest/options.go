@@ -0,0 +1,66 @@
+package rxtest
+
+import "time"
+
+// Option configures a Scheduler.
+type Option interface {
+	apply(*options)
+}
+
+type options struct {
+	frame   time.Duration
+	err     error
+	timeout time.Duration
+}
+
+type funcOption struct {
+	fn func(*options)
+}
+
+func (f *funcOption) apply(o *options) {
+	f.fn(o)
+}
+
+// WithFrame sets how much virtual time a frame of a marble diagram stands for,
+// defaults to a millisecond.
+func WithFrame(d time.Duration) Option {
+	return &funcOption{
+		fn: func(o *options) {
+			o.frame = d
+		},
+	}
+}
+
+// WithError sets the error '#' stands for in marble diagrams, defaults to
+// ErrMarble.
+func WithError(err error) Option {
+	return &funcOption{
+		fn: func(o *options) {
+			o.err = err
+		},
+	}
+}
+
+// WithTimeout sets how long Expect waits in real time for a pipeline to process
+// its source, defaults to 5 seconds.
+func WithTimeout(d time.Duration) Option {
+	return &funcOption{
+		fn: func(o *options) {
+			o.timeout = d
+		},
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		frame:   time.Millisecond,
+		err:     ErrMarble,
+		timeout: 5 * time.Second,
+	}
+
+	for _, opt := range opts {
+		opt.apply(o)
+	}
+
+	return o
+}
@@ -0,0 +1,260 @@
+// Package rxtest tests rx pipelines with marble diagrams, in the style of the
+// RxJS TestScheduler. A marble diagram draws the items of a stream over
+// virtual time, one character per frame:
+//
+//	a     an item, see Values, emitted at the current frame
+//	-     a frame passes without any item
+//	(ab)  items emitted together at the same frame
+//	|     the stream ends
+//	#     the stream fails, see WithError
+//
+// Spaces are ignored. A Scheduler turns a diagram into a source Operable whose
+// time-based operators, such as Debounce and Timestamp, follow a virtual clock
+// set to the frame of the item being processed. Pipelines then run as fast as
+// they can, without sleeps, and their output is compared to another diagram.
+package rxtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/botchris/go-observer"
+	"github.com/botchris/go-observer/rx"
+	"github.com/stretchr/testify/assert"
+)
+
+// ErrMarble is the error '#' stands for unless another one is given with
+// WithError.
+var ErrMarble = errors.New("rxtest: marble error")
+
+// Scheduler drives a single source Operable created with Cold over virtual
+// time, and checks the output of the pipeline built on top of it with Expect.
+type Scheduler struct {
+	t      testing.TB
+	opts   *options
+	ctx    context.Context
+	cancel context.CancelFunc
+	epoch  time.Time
+	now    atomic.Int64
+
+	// ready is closed once Expect is watching the output of the pipeline, the
+	// source doesn't emit anything before. settled is closed once the
+	// pipeline has processed every item of a source that doesn't end.
+	ready     chan struct{}
+	settled   chan struct{}
+	readyOnce sync.Once
+
+	mu      sync.Mutex
+	source  *source
+	output  observer.Stream
+	frame   int
+	events  []event
+	stopped bool
+}
+
+// NewScheduler creates a Scheduler for the given test, pipelines are cancelled
+// once the test finishes.
+func NewScheduler(t testing.TB, opts ...Option) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Scheduler{
+		t:       t,
+		opts:    newOptions(opts),
+		ctx:     ctx,
+		cancel:  cancel,
+		epoch:   time.Unix(0, 0).UTC(),
+		ready:   make(chan struct{}),
+		settled: make(chan struct{}),
+	}
+
+	t.Cleanup(func() {
+		s.cancel()
+		s.start()
+	})
+
+	return s
+}
+
+// Now returns the virtual time, it implements rx.Clock.
+func (s *Scheduler) Now() time.Time {
+	return s.Time(int(s.now.Load()))
+}
+
+// Time returns the virtual time of the given frame, e.g. to build the values
+// expected from Timestamp.
+func (s *Scheduler) Time(frame int) time.Time {
+	return s.epoch.Add(time.Duration(frame) * s.opts.frame)
+}
+
+// Cold returns an Operable emitting the items of the given marble diagram,
+// following the virtual clock of this scheduler. Operators can then be applied
+// to it as usual, and its output checked with Expect. A scheduler drives a
+// single source, so Cold must be called only once.
+func (s *Scheduler) Cold(marble string, values Values, opts ...rx.Option) *rx.Operable {
+	s.t.Helper()
+
+	events, err := parse(marble, values, s.opts.err)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.source != nil {
+		s.t.Fatal("rxtest: a scheduler drives a single source")
+	}
+
+	p := observer.NewProperty(nil)
+	stream := p.Observe()
+
+	frames := []int{0}
+	for _, e := range events {
+		if e.End {
+			p.Fail(e.Err)
+		} else {
+			p.Update(e.Value)
+		}
+
+		frames = append(frames, e.Frame)
+	}
+
+	s.source = &source{Stream: stream, scheduler: s, frames: frames}
+
+	return rx.MakeOperable(s.ctx, s.source, append([]rx.Option{rx.WithClock(s)}, opts...)...)
+}
+
+// Expect runs the pipeline whose output is the given Operable, built on top of
+// the source returned by Cold, and checks that it emits the items of the given
+// marble diagram. Items are expected at the frame of the source item whose
+// processing produced them. On failure it reports the diagram that was emitted
+// instead, along with a diff of the items. It returns whether the assertion
+// succeeded.
+func (s *Scheduler) Expect(out *rx.Operable, marble string, values Values) bool {
+	s.t.Helper()
+
+	expected, err := parse(marble, values, s.opts.err)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+
+	s.mu.Lock()
+	if s.source == nil {
+		s.mu.Unlock()
+		s.t.Fatal("rxtest: Cold must be called before Expect")
+	}
+
+	s.output = out.Clone()
+	s.mu.Unlock()
+
+	s.start()
+
+	timeout := time.NewTimer(s.opts.timeout)
+	defer timeout.Stop()
+
+	select {
+	case <-out.Done():
+	case <-s.settled:
+	case <-timeout.C:
+		s.t.Fatalf("rxtest: pipeline did not settle within %s", s.opts.timeout)
+	}
+
+	s.mu.Lock()
+	s.collect()
+	s.stopped = true
+	actual := s.events
+	s.mu.Unlock()
+
+	return assert.Equal(s.t, expected, actual, fmt.Sprintf("Expecting %q but got %q", marble, render(actual, values)))
+}
+
+// start lets the source emit its items.
+func (s *Scheduler) start() {
+	s.readyOnce.Do(func() {
+		close(s.ready)
+	})
+}
+
+// read is called by the source when the pipeline reads an item emitted at the
+// given frame, the virtual clock moves on to it.
+func (s *Scheduler) read(frame int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.frame = frame
+	s.now.Store(int64(frame))
+}
+
+// idle is called by the source when the pipeline is done processing an item,
+// the items emitted meanwhile are given its frame. last tells whether it was
+// the last item of a source that doesn't end.
+func (s *Scheduler) idle(last bool) {
+	<-s.ready
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stopped {
+		return
+	}
+
+	s.collect()
+
+	if last {
+		select {
+		case <-s.settled:
+		default:
+			close(s.settled)
+		}
+	}
+}
+
+// collect records the items emitted by the pipeline so far at the frame of the
+// last source item, must be called while holding the lock.
+func (s *Scheduler) collect() {
+	if s.output == nil {
+		return
+	}
+
+	for s.output.HasNext() {
+		value := s.output.Next()
+		if s.output.Ended() {
+			s.events = append(s.events, event{Frame: s.frame, End: true, Err: s.output.Err()})
+			return
+		}
+
+		s.events = append(s.events, event{Frame: s.frame, Value: value})
+	}
+}
+
+// source is the input stream of the Operable returned by Cold, it tells the
+// scheduler when the pipeline reads an item and when it is done with it. This
+// relies on operables waiting for their input with Changes and reading it with
+// Next.
+type source struct {
+	observer.Stream
+
+	scheduler *Scheduler
+	frames    []int
+}
+
+func (s *source) Changes() chan struct{} {
+	s.scheduler.idle(int(s.Stream.Seq()) == len(s.frames)-1)
+
+	return s.Stream.Changes()
+}
+
+func (s *source) Next() interface{} {
+	value := s.Stream.Next()
+	s.scheduler.read(s.frames[s.Stream.Seq()])
+
+	return value
+}
+
+func (s *source) Clone() observer.Stream {
+	return &source{Stream: s.Stream.Clone(), scheduler: s.scheduler, frames: s.frames}
+}
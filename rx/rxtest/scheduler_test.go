@@ -0,0 +1,96 @@
+package rxtest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/botchris/go-observer/rx"
+	"github.com/botchris/go-observer/rx/rxtest"
+	"github.com/stretchr/testify/require"
+)
+
+// recorderT records the failures reported by the assertions under test.
+type recorderT struct {
+	testing.TB
+	failures []string
+}
+
+func (t *recorderT) Helper() {}
+
+func (t *recorderT) Errorf(format string, args ...interface{}) {
+	t.failures = append(t.failures, fmt.Sprintf(format, args...))
+}
+
+func TestScheduler(t *testing.T) {
+	t.Run("GIVEN a mapped source WHEN run THEN items are emitted at the same frames", func(t *testing.T) {
+		s := rxtest.NewScheduler(t)
+		out := s.Cold("-a-b-|", rxtest.Values{"a": 1, "b": 2}).
+			Map(func(_ context.Context, v interface{}) interface{} {
+				return v.(int) * 10
+			})
+
+		s.Expect(out, "-x-y-|", rxtest.Values{"x": 10, "y": 20})
+	})
+
+	t.Run("GIVEN a filtered source WHEN run THEN filtered items leave their frames empty", func(t *testing.T) {
+		s := rxtest.NewScheduler(t)
+		out := s.Cold("-a-b-c-d-|", rxtest.Values{"a": 1, "b": 2, "c": 3, "d": 4}).
+			Filter(func(_ context.Context, v interface{}) bool {
+				return v.(int)%2 == 0
+			})
+
+		s.Expect(out, "---b---d-|", rxtest.Values{"b": 2, "d": 4})
+	})
+
+	t.Run("GIVEN a debounced source WHEN run THEN items follow the virtual clock", func(t *testing.T) {
+		s := rxtest.NewScheduler(t, rxtest.WithFrame(10*time.Millisecond))
+		out := s.Cold("ab-c---d|", nil).Debounce(25 * time.Millisecond)
+
+		s.Expect(out, "---c---d|", nil)
+	})
+
+	t.Run("GIVEN a timestamped source WHEN run THEN items are stamped with the virtual time", func(t *testing.T) {
+		s := rxtest.NewScheduler(t, rxtest.WithFrame(time.Second))
+		out := s.Cold("-a--b|", nil).Timestamp()
+
+		s.Expect(out, "-x--y|", rxtest.Values{
+			"x": rx.TimestampItem{Timestamp: s.Time(1), Item: "a"},
+			"y": rx.TimestampItem{Timestamp: s.Time(4), Item: "b"},
+		})
+	})
+
+	t.Run("GIVEN items emitted on end WHEN run THEN they are grouped with the end", func(t *testing.T) {
+		s := rxtest.NewScheduler(t)
+		out := s.Cold("-a-(bc)-|", nil).Last()
+
+		s.Expect(out, "--------(c|)", nil)
+	})
+
+	t.Run("GIVEN a failing source WHEN run THEN the failure is passed through", func(t *testing.T) {
+		failure := errors.New("failure")
+		s := rxtest.NewScheduler(t, rxtest.WithError(failure))
+		out := s.Cold("-a--#", nil)
+
+		s.Expect(out, "-a--#", nil)
+	})
+
+	t.Run("GIVEN a source that doesn't end WHEN run THEN its items are expected", func(t *testing.T) {
+		s := rxtest.NewScheduler(t)
+		out := s.Cold("-a-b--", nil)
+
+		s.Expect(out, "-a-b", nil)
+	})
+
+	t.Run("GIVEN an unexpected output WHEN run THEN the emitted marble diagram is reported", func(t *testing.T) {
+		rt := &recorderT{TB: t}
+		s := rxtest.NewScheduler(rt)
+		out := s.Cold("-a-b-|", nil)
+
+		require.False(t, s.Expect(out, "-a-c-|", nil))
+		require.Len(t, rt.failures, 1)
+		require.Contains(t, rt.failures[0], `Expecting "-a-c-|" but got "-a-b-|"`)
+	})
+}
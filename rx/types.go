@@ -5,17 +5,31 @@ import "context"
 type (
 	startStrategy int
 
+	overflowStrategy int
+
+	// PredicateOf defines a func that returns a bool from an input value of type T.
+	PredicateOf[T any] func(ctx context.Context, v T) bool
+
+	// MapperOf defines a function that computes a value of type U from an input value of type T.
+	MapperOf[T any, U any] func(ctx context.Context, i T) U
+
+	// ComparatorOf defines a func that returns an int:
+	// - 0 if two elements are equals
+	// - A negative value if the first argument is less than the second
+	// - A positive value if the first argument is greater than the second
+	ComparatorOf[T any] func(ctx context.Context, a T, b T) int
+
 	// Predicate defines a func that returns a bool from an input value.
-	Predicate func(ctx context.Context, v interface{}) bool
+	Predicate = PredicateOf[interface{}]
 
 	// Mapper defines a function that computes a value from an input value.
-	Mapper func(ctx context.Context, i interface{}) interface{}
+	Mapper = MapperOf[interface{}, interface{}]
 
 	// Comparator defines a func that returns an int:
 	// - 0 if two elements are equals
 	// - A negative value if the first argument is less than the second
 	// - A positive value if the first argument is greater than the second
-	Comparator func(ctx context.Context, a interface{}, b interface{}) int
+	Comparator = ComparatorOf[interface{}]
 )
 
 // List of known starting strategies
@@ -23,3 +37,13 @@ const (
 	Lazy  startStrategy = 0
 	Eager startStrategy = 1
 )
+
+// List of known overflow strategies
+const (
+	// Block waits for the receiver to make room in the channel.
+	Block overflowStrategy = 0
+	// DropNewest discards the item being sent when the channel is full.
+	DropNewest overflowStrategy = 1
+	// DropOldest discards the oldest item buffered in the channel to make room for the item being sent.
+	DropOldest overflowStrategy = 2
+)
@@ -0,0 +1,158 @@
+package observer
+
+import (
+	"context"
+	"io"
+	"math/rand/v2"
+	"reflect"
+	"slices"
+)
+
+// Select waits until any of the given streams has a new value, advances it
+// and returns its index and the new value. No goroutines are involved. When
+// several streams have a new value one of them is chosen at random, so none of
+// them is starved.
+//
+// Streams that have already ended are ignored. When the chosen stream reaches
+// its end, err is io.EOF or the error its property failed with. It returns -1
+// and io.EOF if every stream has ended, or the context error if ctx is done
+// first, in which case no stream is advanced.
+//
+// Use a Selector to wait on the same set of streams repeatedly.
+func Select(ctx context.Context, streams ...Stream) (index int, value interface{}, err error) {
+	start := 0
+	if len(streams) > 0 {
+		start = rand.IntN(len(streams))
+	}
+
+	return selectStream(ctx, streams, make([]reflect.SelectCase, 0, len(streams)+1), start)
+}
+
+// Selector waits on a dynamic set of streams without involving goroutines,
+// see Select. Streams can be added and removed in constant time, and are
+// served in turns when several of them have new values. Like streams, a
+// Selector is not goroutine safe.
+type Selector struct {
+	streams []Stream
+	index   map[Stream]int
+	cases   []reflect.SelectCase
+	next    int
+}
+
+// NewSelector creates a Selector waiting on the given streams.
+func NewSelector(streams ...Stream) *Selector {
+	s := &Selector{index: make(map[Stream]int)}
+	s.Add(streams...)
+
+	return s
+}
+
+// Add adds the given streams to this selector, streams already added are
+// ignored.
+func (s *Selector) Add(streams ...Stream) {
+	for _, stream := range streams {
+		if _, ok := s.index[stream]; ok {
+			continue
+		}
+
+		s.index[stream] = len(s.streams)
+		s.streams = append(s.streams, stream)
+	}
+}
+
+// Remove removes the given stream from this selector, if it was added. The
+// stream is not closed.
+func (s *Selector) Remove(stream Stream) {
+	i, ok := s.index[stream]
+	if !ok {
+		return
+	}
+
+	last := len(s.streams) - 1
+	s.streams[i] = s.streams[last]
+	s.index[s.streams[i]] = i
+	s.streams[last] = nil
+	s.streams = s.streams[:last]
+	delete(s.index, stream)
+}
+
+// Len returns the number of streams in this selector.
+func (s *Selector) Len() int {
+	return len(s.streams)
+}
+
+// Select waits until any stream of this selector has a new value, advances it
+// and returns it along with the new value. See the Select function for the
+// returned errors, the returned stream is nil if ctx is done or every stream
+// has ended.
+func (s *Selector) Select(ctx context.Context) (Stream, interface{}, error) {
+	s.cases = slices.Grow(s.cases[:0], len(s.streams)+1)
+
+	i, value, err := selectStream(ctx, s.streams, s.cases, s.next)
+	clear(s.cases[:cap(s.cases)])
+
+	if i < 0 {
+		return nil, value, err
+	}
+
+	s.next = i + 1
+
+	return s.streams[i], value, err
+}
+
+// selectStream implements Select, looking for a stream with a new value from
+// the given start index onwards. The capacity of cases is reused to wait on
+// the streams.
+func selectStream(ctx context.Context, streams []Stream, cases []reflect.SelectCase, start int) (int, interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return -1, nil, err
+	}
+
+	pending := 0
+	for i := range streams {
+		j := (start + i) % len(streams)
+		if streams[j].HasNext() {
+			return advanceStream(streams, j)
+		}
+
+		if !streams[j].Ended() {
+			pending++
+		}
+	}
+
+	if pending == 0 {
+		return -1, nil, io.EOF
+	}
+
+	cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())})
+	for _, stream := range streams {
+		c := reflect.SelectCase{Dir: reflect.SelectRecv}
+		if !stream.Ended() {
+			c.Chan = reflect.ValueOf(stream.Changes())
+		}
+
+		cases = append(cases, c)
+	}
+
+	chosen, _, _ := reflect.Select(cases)
+	if chosen == 0 {
+		return -1, nil, ctx.Err()
+	}
+
+	return advanceStream(streams, chosen-1)
+}
+
+// advanceStream advances the i-th stream and returns its new value, and
+// io.EOF or its failure if the stream has ended.
+func advanceStream(streams []Stream, i int) (int, interface{}, error) {
+	value := streams[i].Next()
+	if !streams[i].Ended() {
+		return i, value, nil
+	}
+
+	if err := streams[i].Err(); err != nil {
+		return i, value, err
+	}
+
+	return i, value, io.EOF
+}
@@ -0,0 +1,132 @@
+package observer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestSelect(t *testing.T) {
+	a := NewProperty(0)
+	b := NewProperty(0)
+	streams := []Stream{a.Observe(), b.Observe()}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		b.Update(1)
+	}()
+
+	i, val, err := Select(context.Background(), streams...)
+	if i != 1 || val != 1 || err != nil {
+		t.Fatalf("Expecting 1 from stream 1 but got %#v from stream %#v (%#v)\n", val, i, err)
+	}
+
+	a.Update(2)
+	i, val, err = Select(context.Background(), streams...)
+	if i != 0 || val != 2 || err != nil {
+		t.Fatalf("Expecting 2 from stream 0 but got %#v from stream %#v (%#v)\n", val, i, err)
+	}
+}
+
+func TestSelectEnd(t *testing.T) {
+	a := NewProperty(0)
+	b := NewProperty(0)
+	streams := []Stream{a.Observe(), b.Observe()}
+
+	failure := errors.New("failure")
+	a.Fail(failure)
+	if i, _, err := Select(context.Background(), streams...); i != 0 || err != failure {
+		t.Fatalf("Expecting stream 0 to fail but got %#v from stream %#v\n", err, i)
+	}
+
+	b.End()
+	if i, val, err := Select(context.Background(), streams...); i != 1 || val != io.EOF || err != io.EOF {
+		t.Fatalf("Expecting stream 1 to end but got %#v from stream %#v\n", err, i)
+	}
+
+	if i, _, err := Select(context.Background(), streams...); i != -1 || err != io.EOF {
+		t.Fatalf("Expecting every stream to be ended but got %#v from stream %#v\n", err, i)
+	}
+}
+
+func TestSelectContext(t *testing.T) {
+	prop := NewProperty(0)
+	stream := prop.Observe()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if i, _, err := Select(ctx, stream); i != -1 || err != context.DeadlineExceeded {
+		t.Fatalf("Expecting context.DeadlineExceeded but got %#v from stream %#v\n", err, i)
+	}
+
+	prop.Update(1)
+	if i, _, err := Select(ctx, stream); i != -1 || err != context.DeadlineExceeded {
+		t.Fatalf("Expecting context.DeadlineExceeded but got %#v from stream %#v\n", err, i)
+	}
+	if val := stream.Value(); val != 0 {
+		t.Fatalf("Expecting stream not to be advanced but got %#v\n", val)
+	}
+}
+
+func TestSelector(t *testing.T) {
+	props := make([]Property, 100)
+	selector := NewSelector()
+	for i := range props {
+		props[i] = NewProperty(0)
+		selector.Add(props[i].Observe())
+	}
+	if n := selector.Len(); n != 100 {
+		t.Fatalf("Expecting 100 streams but got %#v\n", n)
+	}
+
+	for _, prop := range props {
+		prop.Update(1)
+	}
+
+	seen := make(map[Stream]bool)
+	for range props {
+		stream, val, err := selector.Select(context.Background())
+		if val != 1 || err != nil {
+			t.Fatalf("Expecting 1 but got %#v (%#v)\n", val, err)
+		}
+		if seen[stream] {
+			t.Fatalf("Expecting every stream to be served once\n")
+		}
+
+		seen[stream] = true
+	}
+
+	for stream := range seen {
+		selector.Remove(stream)
+		selector.Remove(stream)
+	}
+	if n := selector.Len(); n != 0 {
+		t.Fatalf("Expecting no streams but got %#v\n", n)
+	}
+
+	if stream, _, err := selector.Select(context.Background()); stream != nil || err != io.EOF {
+		t.Fatalf("Expecting io.EOF but got %#v\n", err)
+	}
+}
+
+func TestSelectorRemove(t *testing.T) {
+	a := NewProperty(0)
+	b := NewProperty(0)
+	sa, sb := a.Observe(), b.Observe()
+	selector := NewSelector(sa, sb, sa)
+	if n := selector.Len(); n != 2 {
+		t.Fatalf("Expecting 2 streams but got %#v\n", n)
+	}
+
+	selector.Remove(sa)
+	a.Update(1)
+	b.Update(2)
+
+	stream, val, err := selector.Select(context.Background())
+	if stream != sb || val != 2 || err != nil {
+		t.Fatalf("Expecting 2 from the remaining stream but got %#v (%#v)\n", val, err)
+	}
+}
@@ -1,20 +1,57 @@
 package observer
 
-type state struct {
-	value interface{}
-	next  *state
+import (
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+type state[T any] struct {
+	value T
+	seq   uint64
+	time  time.Time
+	ended bool
+	err   error
+	next  atomic.Pointer[state[T]]
 	done  chan struct{}
+
+	// source and headers are set by PropertyOf.UpdateWithMeta, see Meta.
+	source  string
+	headers map[string]string
 }
 
-func newState(value interface{}) *state {
-	return &state{
+func newState[T any](value T) *state[T] {
+	return &state[T]{
 		value: value,
+		time:  time.Now(),
 		done:  make(chan struct{}),
 	}
 }
 
-func (s *state) update(value interface{}) *state {
-	s.next = newState(value)
+// update appends a state holding the given value to the list and notifies
+// the streams waiting on this one.
+func (s *state[T]) update(value T) *state[T] {
+	next := s.stage(newState(value))
 	close(s.done)
-	return s.next
+	return next
+}
+
+// stage appends the given state to the list without notifying the streams
+// waiting on this one, its done channel must be closed afterwards.
+func (s *state[T]) stage(next *state[T]) *state[T] {
+	next.seq = s.seq + 1
+	s.next.Store(next)
+	return next
+}
+
+// eof returns the value held by the final state of a property: io.EOF when T
+// is able to hold it (e.g. untyped properties), or the zero value of T.
+func eof[T any]() T {
+	value, _ := any(io.EOF).(T)
+	return value
+}
+
+// isEOF reports whether the given value is io.EOF.
+func isEOF[T any](value T) bool {
+	return any(value) == io.EOF
 }
@@ -2,12 +2,12 @@ package observer
 
 import "testing"
 
-func testStateNew(t *testing.T, state *state, val interface{}) {
+func testStateNew(t *testing.T, state *state[interface{}], val interface{}) {
 	if state.value != val {
 		t.Fatalf("Expecting %#v but got %#v\n", val, state.value)
 	}
-	if state.next != nil {
-		t.Fatalf("Expecting no next but got %#v\n", state.next)
+	if state.next.Load() != nil {
+		t.Fatalf("Expecting no next but got %#v\n", state.next.Load())
 	}
 	select {
 	case <-state.done:
@@ -17,12 +17,12 @@ func testStateNew(t *testing.T, state *state, val interface{}) {
 }
 
 func TestStateNew(t *testing.T) {
-	state := newState(10)
+	state := newState[interface{}](10)
 	testStateNew(t, state, 10)
 }
 
 func TestStateUpdate(t *testing.T) {
-	state1 := newState(10)
+	state1 := newState[interface{}](10)
 	state2 := state1.update(15)
 	if state1 == state2 {
 		t.Fatalf("Expecting different states\n")
@@ -30,8 +30,8 @@ func TestStateUpdate(t *testing.T) {
 	if state2.value != 15 {
 		t.Fatalf("Expecting 15 but got %#v\n", state1.value)
 	}
-	if state1.next == nil {
-		t.Fatalf("Expecting next but got %#v\n", state1.next)
+	if state1.next.Load() == nil {
+		t.Fatalf("Expecting next but got %#v\n", state1.next.Load())
 	}
 	select {
 	case <-state1.done:
@@ -0,0 +1,179 @@
+package observer
+
+import (
+	"cmp"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// Stats describes how a property is being observed, see PropertyOf.Stats.
+type Stats struct {
+	// Streams is the number of active streams of the property: streams that
+	// have been neither closed nor garbage collected.
+	Streams int
+
+	// Updates is the total number of updates the property went through, which
+	// is also the sequence number of its current value.
+	Updates uint64
+
+	// OldestSeq is the sequence number of the oldest value still retained in
+	// memory, either by the history of the property or by its slowest stream.
+	OldestSeq uint64
+
+	// Observers describes each active stream, sorted from the slowest to the
+	// fastest one.
+	Observers []StreamStats
+}
+
+// StreamStats describes an active stream of a property, see Stats.
+type StreamStats struct {
+	// ID identifies the stream among the streams of its property, streams
+	// are numbered from 1 in the order they are created.
+	ID uint64
+
+	// Label is the label given to the stream with Label, if any.
+	Label string
+
+	// Seq is the sequence number of the current value of the stream.
+	Seq uint64
+
+	// Lag is how many values the stream is behind the current value of the
+	// property.
+	Lag uint64
+}
+
+// Label names the given stream so it can be told apart in the stats of its
+// property, and returns it. Clones of the stream inherit its label. It has no
+// effect on streams not created by this package, such as operables: label
+// the stream they are made from instead.
+func Label[T any](s StreamOf[T], label string) StreamOf[T] {
+	if st, ok := s.(*stream[T]); ok && st.owner != nil {
+		st.owner.observers.Lock()
+		st.tracker.label = label
+		st.owner.observers.Unlock()
+	}
+
+	return s
+}
+
+// observers keeps track of the active streams of a property. Only their
+// trackers are referenced, so the streams that are never closed can still be
+// garbage collected.
+type observers struct {
+	sync.Mutex
+	nextID uint64
+	count  int
+	head   *tracker
+}
+
+// tracker is the part of a stream its property reads when computing its
+// stats. It is kept apart from the stream so the property doesn't retain it.
+// Trackers form a doubly linked list, so streams come and go in constant time.
+type tracker struct {
+	prev, next *tracker
+	removed    bool
+	id         uint64
+	label      string
+
+	// pos mirrors the sequence number of the current state of the stream, so
+	// the property can read it concurrently.
+	pos atomic.Uint64
+}
+
+func (p *property[T]) Stats() Stats {
+	p.RLock()
+	stats := Stats{
+		Updates:   p.state.seq,
+		OldestSeq: p.state.seq,
+	}
+
+	if p.oldest != nil {
+		stats.OldestSeq = p.oldest.seq
+	}
+	p.RUnlock()
+
+	p.observers.Lock()
+	for tr := p.observers.head; tr != nil; tr = tr.next {
+		seq := min(tr.pos.Load(), stats.Updates)
+		stats.Streams++
+		stats.OldestSeq = min(stats.OldestSeq, seq)
+		stats.Observers = append(stats.Observers, StreamStats{
+			ID:    tr.id,
+			Label: tr.label,
+			Seq:   seq,
+			Lag:   stats.Updates - seq,
+		})
+	}
+	p.observers.Unlock()
+
+	slices.SortFunc(stats.Observers, func(a, b StreamStats) int {
+		return cmp.Or(cmp.Compare(b.Lag, a.Lag), cmp.Compare(a.ID, b.ID))
+	})
+
+	return stats
+}
+
+// register starts accounting for the given stream until it is closed or
+// garbage collected, must be called without holding the lock.
+func (p *property[T]) register(s *stream[T]) {
+	defer p.observersChanged()
+
+	tr := s.tracker
+
+	p.observers.Lock()
+	p.observers.nextID++
+	tr.id = p.observers.nextID
+	tr.next = p.observers.head
+	if tr.next != nil {
+		tr.next.prev = tr
+	}
+
+	p.observers.head = tr
+	p.observers.count++
+	p.observers.Unlock()
+
+	s.cleanup = runtime.AddCleanup(s, p.unregister, tr)
+}
+
+// unregister stops accounting for the stream with the given tracker.
+func (p *property[T]) unregister(tr *tracker) {
+	defer p.observersChanged()
+
+	p.observers.Lock()
+	defer p.observers.Unlock()
+
+	if tr.removed {
+		return
+	}
+
+	if tr.prev != nil {
+		tr.prev.next = tr.next
+	} else {
+		p.observers.head = tr.next
+	}
+
+	if tr.next != nil {
+		tr.next.prev = tr.prev
+	}
+
+	tr.prev, tr.next, tr.removed = nil, nil, true
+	p.observers.count--
+}
+
+// observersChanged runs the onObservers hook, must be called without holding
+// the observers lock.
+func (p *property[T]) observersChanged() {
+	if p.onObservers != nil {
+		p.onObservers()
+	}
+}
+
+// observed reports whether this property has any active stream.
+func (p *property[T]) observed() bool {
+	p.observers.Lock()
+	defer p.observers.Unlock()
+
+	return p.observers.count > 0
+}
@@ -0,0 +1,91 @@
+package observer
+
+import (
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	prop := NewProperty(0)
+	slow := Label(prop.Observe(), "slow")
+	prop.Update(1)
+	fast := prop.Observe()
+	prop.Update(2)
+	prop.Update(3)
+	fast.Next()
+
+	stats := prop.Stats()
+	if stats.Streams != 2 {
+		t.Fatalf("Expecting 2 streams but got %#v\n", stats.Streams)
+	}
+	if stats.Updates != 3 {
+		t.Fatalf("Expecting 3 updates but got %#v\n", stats.Updates)
+	}
+	if stats.OldestSeq != 0 {
+		t.Fatalf("Expecting oldest seq 0 but got %#v\n", stats.OldestSeq)
+	}
+	expected := []StreamStats{
+		{ID: 1, Label: "slow", Seq: 0, Lag: 3},
+		{ID: 2, Seq: 2, Lag: 1},
+	}
+	if !reflect.DeepEqual(stats.Observers, expected) {
+		t.Fatalf("Expecting observers %#v but got %#v\n", expected, stats.Observers)
+	}
+
+	slow.Close()
+	stats = prop.Stats()
+	if stats.Streams != 1 || stats.OldestSeq != 2 {
+		t.Fatalf("Expecting 1 stream at seq 2 but got %#v\n", stats)
+	}
+
+	fast.Close()
+	fast.Close()
+	stats = prop.Stats()
+	if stats.Streams != 0 || stats.OldestSeq != 3 || stats.Observers != nil {
+		t.Fatalf("Expecting no streams but got %#v\n", stats)
+	}
+}
+
+func TestStatsHistory(t *testing.T) {
+	prop := NewProperty(0, WithHistoryLimit(2))
+	for i := 1; i <= 5; i++ {
+		prop.Update(i)
+	}
+	if stats := prop.Stats(); stats.OldestSeq != 4 {
+		t.Fatalf("Expecting oldest seq 4 but got %#v\n", stats.OldestSeq)
+	}
+}
+
+func TestStatsClone(t *testing.T) {
+	prop := NewProperty(0)
+	stream := Label(prop.Observe(), "stream")
+	clone := stream.Clone()
+	stats := prop.Stats()
+	if stats.Streams != 2 {
+		t.Fatalf("Expecting 2 streams but got %#v\n", stats.Streams)
+	}
+	for _, o := range stats.Observers {
+		if o.Label != "stream" {
+			t.Fatalf("Expecting clones to inherit labels but got %#v\n", stats.Observers)
+		}
+	}
+	stream.Close()
+	if stats := prop.Stats(); stats.Streams != 1 {
+		t.Fatalf("Expecting 1 stream but got %#v\n", stats.Streams)
+	}
+	clone.Close()
+}
+
+func TestStatsGarbageCollectedStreams(t *testing.T) {
+	prop := NewProperty(0)
+	prop.Observe()
+	for i := 0; i < 10 && prop.Stats().Streams != 0; i++ {
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+	if stats := prop.Stats(); stats.Streams != 0 {
+		t.Fatalf("Expecting no streams but got %#v\n", stats.Streams)
+	}
+}
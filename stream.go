@@ -1,59 +1,197 @@
 package observer
 
-// Stream represents the list of values a property is updated to.  For every
-// property update, that value is appended to the list in the order they
-// happen. The value is discarded once you advance the stream.  Please note
-// that Stream is not goroutine safe: you cannot use the same stream on
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"runtime"
+	"time"
+)
+
+// StreamOf represents the list of values of type T a property is updated to.
+// For every property update, that value is appended to the list in the order
+// they happen. The value is discarded once you advance the stream.  Please
+// note that StreamOf is not goroutine safe: you cannot use the same stream on
 // multiple goroutines concurrently. If you want to use multiple streams for
-// the same property, either use Property.Observe (goroutine-safe) or use
-// Stream.Clone (before passing it to another goroutine).
-type Stream interface {
+// the same property, either use PropertyOf.Observe (goroutine-safe) or use
+// StreamOf.Clone (before passing it to another goroutine).
+type StreamOf[T any] interface {
 	// Value returns the current value for this stream.
-	Value() interface{}
+	Value() T
 
 	// Changes returns the channel that is closed when a new value is available.
 	Changes() chan struct{}
 
 	// Next advances this stream to the next state.
 	// You should never call this unless Changes channel is closed.
-	Next() interface{}
+	Next() T
 
 	// HasNext checks whether there is a new value available.
 	HasNext() bool
 
 	// WaitNext waits for Changes to be closed, advances the stream and returns
 	// the current value.
-	WaitNext() interface{}
+	WaitNext() T
+
+	// Latest advances this stream straight to the newest value of the
+	// property without blocking, and returns how many values it skipped on
+	// the way: consumers that only care about the newest value don't have to
+	// call Next in a loop to catch up. Lagged is reset.
+	Latest() int
+
+	// Drain advances this stream through every pending value without
+	// blocking, up to max values or with no limit if max is not positive, and
+	// returns them in order. The end of the property is not returned as a
+	// value, check Ended. Lagged then reports the values skipped while
+	// draining, if any.
+	Drain(max int) []T
 
 	// Clone creates a new independent stream from this one but sharing the same
 	// Property. Updates to the property will be reflected in both streams but
 	// they may have different values depending on when they advance the stream
 	// with Next.
-	Clone() Stream
+	Clone() StreamOf[T]
+
+	// Ended reports whether this stream has reached the end of the property,
+	// no further values will be available once ended. Untyped streams hold a
+	// io.EOF value at this point.
+	Ended() bool
+
+	// Err returns the error the property failed with once this stream has
+	// reached its end. It returns nil while the stream has not ended, or if
+	// the property ended without failing.
+	Err() error
+
+	// WaitNextContext is like WaitNext but gives up when ctx is done, in which
+	// case the stream is not advanced and the context error is returned.
+	WaitNextContext(ctx context.Context) (T, error)
+
+	// WaitUntil advances the stream until its value satisfies the predicate,
+	// the current value is checked first. It returns the matching value, or an
+	// error if ctx is done or the stream ends before any value matches: io.EOF
+	// when the property ended, or the error it failed with.
+	WaitUntil(ctx context.Context, predicate func(T) bool) (T, error)
+
+	// Values returns an iterator over the values that follow the current one,
+	// advancing this stream as it goes. Iteration stops when the stream ends
+	// or ctx is done; check Err and ctx.Err to tell why. No goroutines are
+	// involved, so breaking out of the loop leaves nothing behind. The
+	// method is not named All since operables, which are streams too, already
+	// have an All operator; see the All function instead.
+	Values(ctx context.Context) iter.Seq[T]
+
+	// Seq returns the sequence number of the current value of this stream.
+	// Every property update is given a monotonically increasing sequence
+	// number, starting with 0 for the initial value of the property.
+	Seq() uint64
+
+	// Lagged reports the values skipped by the last call to Next or WaitNext
+	// because the property discarded them from its history before this stream
+	// could read them, see WithHistoryLimit. It returns nil when no values
+	// were skipped.
+	Lagged() *Lagged
+
+	// Meta describes the update that set the current value of this stream:
+	// when it happened, its sequence number, and the source and headers given
+	// to PropertyOf.UpdateWithMeta, which are empty for plain updates.
+	Meta() Meta
+
+	// Close detaches this stream from its property right away: the property
+	// stops accounting for it in its Stats, and the values it was holding on
+	// to are released. The stream then behaves as ended, with Err reporting
+	// ErrClosed. Streams that are not closed are detached once they are
+	// garbage collected. Closing a stream more than once is a no-op.
+	Close()
+}
+
+// ErrClosed is reported by the streams that have been closed.
+var ErrClosed = errors.New("observer: stream closed")
+
+// Lagged describes a stream that fell behind the history limit of its
+// property, it implements the error interface so it can be reported as one.
+type Lagged struct {
+	// Skipped is the number of values that were discarded before the stream
+	// could read them.
+	Skipped int
+}
+
+func (l *Lagged) Error() string {
+	return fmt.Sprintf("observer: stream lagged behind, %d values skipped", l.Skipped)
+}
+
+// Meta describes an update of a property, see PropertyOf.UpdateWithMeta.
+type Meta struct {
+	// Source identifies the publisher that made the update.
+	Source string
+
+	// Headers holds arbitrary data attached to the update, e.g. trace IDs.
+	Headers map[string]string
+
+	// Time is when the property was updated.
+	Time time.Time
+
+	// Seq is the sequence number of the update, see StreamOf.Seq.
+	Seq uint64
 }
 
-type stream struct {
-	state *state
+// Stream represents the list of values a property is updated to.  For every
+// property update, that value is appended to the list in the order they
+// happen. The value is discarded once you advance the stream.  Please note
+// that Stream is not goroutine safe: you cannot use the same stream on
+// multiple goroutines concurrently. If you want to use multiple streams for
+// the same property, either use Property.Observe (goroutine-safe) or use
+// Stream.Clone (before passing it to another goroutine).
+type Stream = StreamOf[interface{}]
+
+type stream[T any] struct {
+	state  *state[T]
+	owner  *property[T]
+	lagged *Lagged
+
+	tracker *tracker
+	cleanup runtime.Cleanup
+	closed  bool
 }
 
-func (s *stream) Clone() Stream {
-	return &stream{state: s.state}
+// newStream creates a stream starting at the given state, registering it to
+// its owner property if any.
+func newStream[T any](owner *property[T], start *state[T]) *stream[T] {
+	s := &stream[T]{state: start, owner: owner}
+
+	if owner != nil {
+		s.tracker = &tracker{}
+		s.track()
+		owner.register(s)
+	}
+
+	return s
 }
 
-func (s *stream) Value() interface{} {
+func (s *stream[T]) Clone() StreamOf[T] {
+	clone := newStream(s.owner, s.state)
+	if s.tracker != nil && s.tracker.label != "" {
+		Label[T](clone, s.tracker.label)
+	}
+
+	return clone
+}
+
+func (s *stream[T]) Value() T {
 	return s.state.value
 }
 
-func (s *stream) Changes() chan struct{} {
+func (s *stream[T]) Changes() chan struct{} {
 	return s.state.done
 }
 
-func (s *stream) Next() interface{} {
-	s.state = s.state.next
+func (s *stream[T]) Next() T {
+	s.advance()
 	return s.state.value
 }
 
-func (s *stream) HasNext() bool {
+func (s *stream[T]) HasNext() bool {
 	select {
 	case <-s.state.done:
 		return true
@@ -62,8 +200,190 @@ func (s *stream) HasNext() bool {
 	}
 }
 
-func (s *stream) WaitNext() interface{} {
+func (s *stream[T]) WaitNext() T {
 	<-s.state.done
-	s.state = s.state.next
+	s.advance()
 	return s.state.value
 }
+
+func (s *stream[T]) Latest() int {
+	s.lagged = nil
+	if !s.HasNext() {
+		return 0
+	}
+
+	if s.owner == nil {
+		skipped := -1
+		for s.HasNext() {
+			s.advance()
+			skipped++
+		}
+
+		return skipped
+	}
+
+	s.owner.RLock()
+	latest := s.owner.state
+	s.owner.RUnlock()
+
+	skipped := int(latest.seq - s.state.seq - 1)
+	s.state = latest
+	s.track()
+
+	return skipped
+}
+
+func (s *stream[T]) Drain(max int) []T {
+	var values []T
+	skipped := 0
+
+	for (max <= 0 || len(values) < max) && s.HasNext() {
+		s.advance()
+		if s.lagged != nil {
+			skipped += s.lagged.Skipped
+		}
+
+		if s.state.ended {
+			break
+		}
+
+		values = append(values, s.state.value)
+	}
+
+	s.lagged = nil
+	if skipped > 0 {
+		s.lagged = &Lagged{Skipped: skipped}
+	}
+
+	return values
+}
+
+func (s *stream[T]) Ended() bool {
+	return s.state.ended
+}
+
+func (s *stream[T]) Err() error {
+	return s.state.err
+}
+
+func (s *stream[T]) Values(ctx context.Context) iter.Seq[T] {
+	return values[T](ctx, s)
+}
+
+// All returns an iterator over the values that follow the current one of the
+// given stream, it is a shortcut for StreamOf.Values that reads well in range
+// loops: for v := range observer.All(ctx, stream).
+func All[T any](ctx context.Context, s StreamOf[T]) iter.Seq[T] {
+	return s.Values(ctx)
+}
+
+// values implements StreamOf.Values on top of the other stream methods.
+func values[T any](ctx context.Context, s StreamOf[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for {
+			value, err := s.WaitNextContext(ctx)
+			if err != nil || s.Ended() || !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+func (s *stream[T]) Seq() uint64 {
+	return s.state.seq
+}
+
+func (s *stream[T]) Lagged() *Lagged {
+	return s.lagged
+}
+
+func (s *stream[T]) Meta() Meta {
+	return Meta{
+		Source:  s.state.source,
+		Headers: s.state.headers,
+		Time:    s.state.time,
+		Seq:     s.state.seq,
+	}
+}
+
+// advance moves this stream to the next state. If the next state has been
+// discarded from the property history, it jumps to the oldest retained one.
+func (s *stream[T]) advance() {
+	next := s.state.next.Load()
+	s.lagged = nil
+
+	if next == nil && s.owner != nil {
+		next = s.owner.oldestRetained()
+		s.lagged = &Lagged{Skipped: int(next.seq - s.state.seq - 1)}
+	}
+
+	s.state = next
+	s.track()
+}
+
+// track lets the owner know about the current state of this stream, if any.
+func (s *stream[T]) track() {
+	if s.tracker != nil {
+		s.tracker.pos.Store(s.state.seq)
+	}
+}
+
+func (s *stream[T]) Close() {
+	if s.closed {
+		return
+	}
+
+	if s.owner != nil {
+		s.cleanup.Stop()
+		s.owner.unregister(s.tracker)
+	}
+
+	// swap the current state for a detached final one, so neither the rest of
+	// the list nor the property are retained by this stream anymore.
+	closed := newState(eof[T]())
+	closed.seq = s.state.seq
+	closed.ended = true
+	closed.err = ErrClosed
+
+	s.state = closed
+	s.owner = nil
+	s.lagged = nil
+	s.closed = true
+}
+
+func (s *stream[T]) WaitNextContext(ctx context.Context) (T, error) {
+	select {
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	case <-s.state.done:
+		return s.Next(), nil
+	}
+}
+
+func (s *stream[T]) WaitUntil(ctx context.Context, predicate func(T) bool) (T, error) {
+	return waitUntil[T](ctx, s, predicate)
+}
+
+// waitUntil implements StreamOf.WaitUntil on top of the other stream methods.
+func waitUntil[T any](ctx context.Context, s StreamOf[T], predicate func(T) bool) (T, error) {
+	value := s.Value()
+	for {
+		if s.Ended() {
+			if err := s.Err(); err != nil {
+				return value, err
+			}
+
+			return value, io.EOF
+		}
+
+		if predicate(value) {
+			return value, nil
+		}
+
+		var err error
+		if value, err = s.WaitNextContext(ctx); err != nil {
+			return value, err
+		}
+	}
+}
@@ -1,23 +1,26 @@
 package observer
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"reflect"
 	"testing"
 	"time"
 )
 
 func TestStreamInitialValue(t *testing.T) {
-	state := newState(10)
-	stream := &stream{state: state}
+	state := newState[interface{}](10)
+	stream := &stream[interface{}]{state: state}
 	if val := stream.Value(); val != 10 {
 		t.Fatalf("Expecting 10 but got %#v\n", val)
 	}
 }
 
 func TestStreamUpdate(t *testing.T) {
-	state1 := newState(10)
+	state1 := newState[interface{}](10)
 	state2 := state1.update(15)
-	stream := &stream{state: state1}
+	stream := &stream[interface{}]{state: state1}
 	if val := stream.Value(); val != 10 {
 		t.Fatalf("Expecting 10 but got %#v\n", val)
 	}
@@ -28,8 +31,8 @@ func TestStreamUpdate(t *testing.T) {
 }
 
 func TestStreamNextValue(t *testing.T) {
-	state1 := newState(10)
-	stream := &stream{state: state1}
+	state1 := newState[interface{}](10)
+	stream := &stream[interface{}]{state: state1}
 	state2 := state1.update(15)
 	if val := stream.Next(); val != 15 {
 		t.Fatalf("Expecting 15 but got %#v\n", val)
@@ -41,8 +44,8 @@ func TestStreamNextValue(t *testing.T) {
 }
 
 func TestStreamDetectsChanges(t *testing.T) {
-	state := newState(10)
-	stream := &stream{state: state}
+	state := newState[interface{}](10)
+	stream := &stream[interface{}]{state: state}
 	select {
 	case <-stream.Changes():
 		t.Fatalf("Expecting no changes\n")
@@ -73,8 +76,8 @@ func TestStreamDetectsChanges(t *testing.T) {
 }
 
 func TestStreamHasChanges(t *testing.T) {
-	state := newState(10)
-	stream := &stream{state: state}
+	state := newState[interface{}](10)
+	stream := &stream[interface{}]{state: state}
 	if stream.HasNext() {
 		t.Fatalf("Expecting no changes\n")
 	}
@@ -88,8 +91,8 @@ func TestStreamHasChanges(t *testing.T) {
 }
 
 func TestStreamWaitsNext(t *testing.T) {
-	state := newState(10)
-	stream := &stream{state: state}
+	state := newState[interface{}](10)
+	stream := &stream[interface{}]{state: state}
 	for i := 15; i <= 100; i++ {
 		state = state.update(i)
 		if val := stream.WaitNext(); val != i {
@@ -102,8 +105,8 @@ func TestStreamWaitsNext(t *testing.T) {
 }
 
 func TestStreamClone(t *testing.T) {
-	state := newState(10)
-	stream1 := &stream{state: state}
+	state := newState[interface{}](10)
+	stream1 := &stream[interface{}]{state: state}
 	stream2 := stream1.Clone()
 	if stream2.HasNext() {
 		t.Fatalf("Expecting no changes\n")
@@ -180,3 +183,235 @@ func testStreamRead(s Stream, initial, final int, err chan error) {
 	}
 	close(err)
 }
+
+func TestStreamWaitNextContext(t *testing.T) {
+	state := newState[interface{}](10)
+	stream := &stream[interface{}]{state: state}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if val, err := stream.WaitNextContext(ctx); err != context.DeadlineExceeded || val != nil {
+		t.Fatalf("Expecting deadline exceeded but got %#v, %#v\n", val, err)
+	}
+	if val := stream.Value(); val != 10 {
+		t.Fatalf("Expecting 10 but got %#v\n", val)
+	}
+	state.update(15)
+	if val, err := stream.WaitNextContext(context.Background()); err != nil || val != 15 {
+		t.Fatalf("Expecting 15 but got %#v, %#v\n", val, err)
+	}
+}
+
+func TestStreamWaitUntil(t *testing.T) {
+	prop := NewPropertyOf(0)
+	stream := prop.Observe()
+	go func() {
+		for i := 1; i <= 10; i++ {
+			prop.Update(i)
+		}
+		prop.End()
+	}()
+	val, err := stream.WaitUntil(context.Background(), func(v int) bool {
+		return v > 5
+	})
+	if err != nil || val != 6 {
+		t.Fatalf("Expecting 6 but got %#v, %#v\n", val, err)
+	}
+	val, err = stream.WaitUntil(context.Background(), func(v int) bool {
+		return v > 5
+	})
+	if err != nil || val != 6 {
+		t.Fatalf("Expecting 6 but got %#v, %#v\n", val, err)
+	}
+	_, err = stream.WaitUntil(context.Background(), func(v int) bool {
+		return v > 10
+	})
+	if err != io.EOF {
+		t.Fatalf("Expecting io.EOF but got %#v\n", err)
+	}
+}
+
+func TestStreamSeq(t *testing.T) {
+	prop := NewPropertyOf(10)
+	stream := prop.Observe()
+	if seq := stream.Seq(); seq != 0 {
+		t.Fatalf("Expecting 0 but got %#v\n", seq)
+	}
+	prop.Update(15, 20)
+	for i := uint64(1); i <= 2; i++ {
+		stream.Next()
+		if seq := stream.Seq(); seq != i {
+			t.Fatalf("Expecting %#v but got %#v\n", i, seq)
+		}
+	}
+	if seq := stream.Clone().Seq(); seq != 2 {
+		t.Fatalf("Expecting 2 but got %#v\n", seq)
+	}
+}
+
+func TestStreamValues(t *testing.T) {
+	prop := NewPropertyOf(0)
+	stream := prop.Observe()
+	prop.Update(1, 2, 3)
+	var read []int
+	for val := range stream.Values(context.Background()) {
+		read = append(read, val)
+		if val == 2 {
+			break
+		}
+	}
+	if len(read) != 2 || read[0] != 1 || read[1] != 2 {
+		t.Fatalf("Expecting [1 2] but got %#v\n", read)
+	}
+	prop.Update(4)
+	prop.End()
+	read = nil
+	for val := range stream.Values(context.Background()) {
+		read = append(read, val)
+	}
+	if len(read) != 2 || read[0] != 3 || read[1] != 4 {
+		t.Fatalf("Expecting [3 4] but got %#v\n", read)
+	}
+	if !stream.Ended() {
+		t.Fatalf("Expecting end of stream\n")
+	}
+}
+
+func TestAll(t *testing.T) {
+	prop := NewPropertyOf(0)
+	stream := prop.Observe()
+	prop.Update(1, 2)
+	prop.End()
+	var read []int
+	for val := range All(context.Background(), stream) {
+		read = append(read, val)
+	}
+	if len(read) != 2 || read[0] != 1 || read[1] != 2 {
+		t.Fatalf("Expecting [1 2] but got %#v\n", read)
+	}
+}
+
+func TestStreamValuesContext(t *testing.T) {
+	prop := NewProperty(0)
+	stream := prop.Observe()
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		prop.Update(1)
+		time.Sleep(100 * time.Millisecond)
+		cancel()
+	}()
+	var read []interface{}
+	for val := range stream.Values(ctx) {
+		read = append(read, val)
+	}
+	if len(read) != 1 || read[0] != 1 {
+		t.Fatalf("Expecting [1] but got %#v\n", read)
+	}
+	if ctx.Err() != context.Canceled {
+		t.Fatalf("Expecting context canceled but got %#v\n", ctx.Err())
+	}
+}
+
+func TestStreamClose(t *testing.T) {
+	prop := NewProperty(1)
+	stream := prop.Observe()
+	prop.Update(2)
+	stream.Close()
+	stream.Close()
+	if !stream.Ended() || stream.Err() != ErrClosed {
+		t.Fatalf("Expecting closed stream but got %#v\n", stream.Err())
+	}
+	if val := stream.Value(); val != io.EOF {
+		t.Fatalf("Expecting io.EOF but got %#v\n", val)
+	}
+	if stream.HasNext() {
+		t.Fatalf("Expecting no next value\n")
+	}
+	if stats := prop.Stats(); stats.Streams != 0 {
+		t.Fatalf("Expecting no streams but got %#v\n", stats.Streams)
+	}
+}
+
+func TestStreamCloseReleasesStates(t *testing.T) {
+	prop := NewProperty(0)
+	stream := prop.Observe().(*stream[interface{}])
+	head := stream.state
+	stream.Close()
+	if stream.state == head || stream.state.next.Load() != nil || stream.owner != nil {
+		t.Fatalf("Expecting states to be released\n")
+	}
+	if val := stream.Clone().Value(); val != io.EOF {
+		t.Fatalf("Expecting io.EOF but got %#v\n", val)
+	}
+}
+
+func TestStreamLatest(t *testing.T) {
+	prop := NewProperty(0)
+	stream := prop.Observe()
+	if skipped := stream.Latest(); skipped != 0 || stream.Value() != 0 {
+		t.Fatalf("Expecting to stay at 0 but got %#v after skipping %#v\n", stream.Value(), skipped)
+	}
+
+	prop.Update(1)
+	if skipped := stream.Latest(); skipped != 0 || stream.Value() != 1 {
+		t.Fatalf("Expecting 1 without skipping but got %#v after skipping %#v\n", stream.Value(), skipped)
+	}
+
+	prop.Update(2, 3, 4, 5)
+	if skipped := stream.Latest(); skipped != 3 || stream.Value() != 5 {
+		t.Fatalf("Expecting 5 after skipping 3 but got %#v after skipping %#v\n", stream.Value(), skipped)
+	}
+	if seq := stream.Seq(); seq != 5 {
+		t.Fatalf("Expecting seq 5 but got %#v\n", seq)
+	}
+	if stream.HasNext() {
+		t.Fatalf("Expecting no next value\n")
+	}
+
+	prop.Update(6)
+	prop.End()
+	if skipped := stream.Latest(); skipped != 1 || !stream.Ended() {
+		t.Fatalf("Expecting to end after skipping 1 but got %#v\n", skipped)
+	}
+}
+
+func TestStreamDrain(t *testing.T) {
+	prop := NewProperty(0)
+	stream := prop.Observe()
+	if values := stream.Drain(0); values != nil {
+		t.Fatalf("Expecting no values but got %#v\n", values)
+	}
+
+	prop.Update(1, 2, 3, 4, 5)
+	if values := stream.Drain(2); !reflect.DeepEqual(values, []interface{}{1, 2}) {
+		t.Fatalf("Expecting [1 2] but got %#v\n", values)
+	}
+
+	prop.End()
+	if values := stream.Drain(0); !reflect.DeepEqual(values, []interface{}{3, 4, 5}) {
+		t.Fatalf("Expecting [3 4 5] but got %#v\n", values)
+	}
+	if !stream.Ended() {
+		t.Fatalf("Expecting stream to be ended\n")
+	}
+}
+
+func TestStreamDrainLagged(t *testing.T) {
+	prop := NewProperty(0, WithHistoryLimit(2))
+	stream := prop.Observe()
+	for i := 1; i <= 10; i++ {
+		prop.Update(i)
+	}
+
+	if values := stream.Drain(0); !reflect.DeepEqual(values, []interface{}{9, 10}) {
+		t.Fatalf("Expecting [9 10] but got %#v\n", values)
+	}
+	if lagged := stream.Lagged(); lagged == nil || lagged.Skipped != 8 {
+		t.Fatalf("Expecting 8 values skipped but got %#v\n", lagged)
+	}
+
+	prop.Update(11)
+	stream.Drain(0)
+	if lagged := stream.Lagged(); lagged != nil {
+		t.Fatalf("Expecting no values skipped but got %#v\n", lagged)
+	}
+}
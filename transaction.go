@@ -0,0 +1,128 @@
+package observer
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	// ErrTxEnded is returned when committing a transaction that updates a
+	// property that has already ended.
+	ErrTxEnded = errors.New("observer: transaction updates an ended property")
+
+	// ErrTxUnsupported is returned when committing a transaction that updates
+	// a property not created by this package, e.g. with NewProperty.
+	ErrTxUnsupported = errors.New("observer: property does not support transactions")
+)
+
+// propertyIDs numbers properties as they are created, transactions lock their
+// participants in that order so they never deadlock each other.
+var propertyIDs atomic.Uint64
+
+// commits is held while transactions notify the streams of their participants,
+// derived properties hold it while they read their sources so a transaction
+// updating several sources is seen as a single step.
+var commits sync.RWMutex
+
+// Tx collects the updates of a transaction, see Transaction. It must not be
+// used once the transaction function returns.
+type Tx struct {
+	updates      []func() func()
+	participants map[uint64]participant
+	err          error
+}
+
+// participant is implemented by the properties that can be updated in a
+// transaction.
+type participant interface {
+	sync.Locker
+	isEnded() bool
+}
+
+// Transaction runs fn to collect a set of updates and then commits them all at
+// once: every property involved is locked, in the same order for every
+// transaction, and updated before any of their streams is notified. Readers
+// never see some of the updates applied without the others, and properties
+// derived from several of them are recomputed once.
+//
+// The commit is all-or-nothing: no update is applied if fn panics, or if any
+// of the properties has ended, in which case ErrTxEnded is returned. Updating
+// with io.EOF ends the property as part of the commit.
+func Transaction(fn func(tx *Tx)) error {
+	tx := &Tx{participants: make(map[uint64]participant)}
+	fn(tx)
+
+	return tx.commit()
+}
+
+// Update adds the given value to the updates of this transaction, updates of
+// the same property are applied in order.
+func (tx *Tx) Update(p Property, value interface{}) {
+	UpdateOf(tx, p, value)
+}
+
+// UpdateOf is like Tx.Update but for typed properties.
+func UpdateOf[T any](tx *Tx, p PropertyOf[T], value T) {
+	prop, ok := p.(*property[T])
+	if !ok {
+		tx.err = ErrTxUnsupported
+		return
+	}
+
+	tx.participants[prop.id] = prop
+	tx.updates = append(tx.updates, func() func() {
+		prev := prop.stage(value, false, nil)
+		return func() {
+			prop.notify(prev)
+		}
+	})
+}
+
+// commit applies the updates collected by this transaction.
+func (tx *Tx) commit() error {
+	if tx.err != nil {
+		return tx.err
+	}
+
+	ids := make([]uint64, 0, len(tx.participants))
+	for id := range tx.participants {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+
+	for _, id := range ids {
+		tx.participants[id].Lock()
+		defer tx.participants[id].Unlock()
+	}
+
+	for _, p := range tx.participants {
+		if p.isEnded() {
+			return ErrTxEnded
+		}
+	}
+
+	notify := make([]func(), len(tx.updates))
+	for i, update := range tx.updates {
+		notify[i] = update()
+	}
+
+	commits.Lock()
+	defer commits.Unlock()
+
+	for _, fn := range notify {
+		fn()
+	}
+
+	return nil
+}
+
+// isEnded reports whether this property has ended, must be called while
+// holding the lock.
+func (p *property[T]) isEnded() bool {
+	return p.ended
+}
@@ -0,0 +1,232 @@
+package observer
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTransaction(t *testing.T) {
+	balance := NewPropertyOf(100)
+	last := NewProperty(nil)
+	stream := balance.Observe()
+
+	err := Transaction(func(tx *Tx) {
+		UpdateOf(tx, balance, 80)
+		tx.Update(last, "withdraw 20")
+	})
+	if err != nil {
+		t.Fatalf("Expecting no error but got %#v\n", err)
+	}
+	if val := balance.Value(); val != 80 {
+		t.Fatalf("Expecting 80 but got %#v\n", val)
+	}
+	if val := last.Value(); val != "withdraw 20" {
+		t.Fatalf("Expecting withdraw 20 but got %#v\n", val)
+	}
+	if val := stream.Next(); val != 80 {
+		t.Fatalf("Expecting 80 but got %#v\n", val)
+	}
+}
+
+func TestTransactionEnded(t *testing.T) {
+	balance := NewProperty(100)
+	last := NewProperty(nil)
+	last.End()
+
+	err := Transaction(func(tx *Tx) {
+		tx.Update(balance, 80)
+		tx.Update(last, "withdraw 20")
+	})
+	if err != ErrTxEnded {
+		t.Fatalf("Expecting ErrTxEnded but got %#v\n", err)
+	}
+	if val := balance.Value(); val != 100 {
+		t.Fatalf("Expecting 100 but got %#v\n", val)
+	}
+}
+
+func TestTransactionPanic(t *testing.T) {
+	balance := NewProperty(100)
+
+	func() {
+		defer func() {
+			recover()
+		}()
+
+		Transaction(func(tx *Tx) {
+			tx.Update(balance, 80)
+			panic("boom")
+		})
+	}()
+
+	if val := balance.Value(); val != 100 {
+		t.Fatalf("Expecting 100 but got %#v\n", val)
+	}
+}
+
+func TestTransactionEnd(t *testing.T) {
+	prop := NewProperty(1)
+	stream := prop.Observe()
+
+	err := Transaction(func(tx *Tx) {
+		tx.Update(prop, 2)
+		tx.Update(prop, io.EOF)
+	})
+	if err != nil {
+		t.Fatalf("Expecting no error but got %#v\n", err)
+	}
+	if val := stream.Next(); val != 2 {
+		t.Fatalf("Expecting 2 but got %#v\n", val)
+	}
+	if stream.Next(); !stream.Ended() {
+		t.Fatalf("Expecting stream to be ended\n")
+	}
+
+	select {
+	case <-prop.Done():
+	default:
+		t.Fatalf("Expecting property to be done\n")
+	}
+}
+
+func TestTransactionUnsupported(t *testing.T) {
+	var custom struct {
+		Property
+	}
+	custom.Property = NewProperty(1)
+
+	err := Transaction(func(tx *Tx) {
+		tx.Update(&custom, 2)
+	})
+	if err != ErrTxUnsupported {
+		t.Fatalf("Expecting ErrTxUnsupported but got %#v\n", err)
+	}
+}
+
+func TestTransactionConsistent(t *testing.T) {
+	a := NewProperty(0)
+	b := NewProperty(0)
+
+	var calls atomic.Int64
+	diff := Derive(func(values ...interface{}) interface{} {
+		calls.Add(1)
+		return values[0].(int) - values[1].(int)
+	}, a, b)
+
+	stream := diff.Observe()
+	calls.Store(0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// updated in the opposite order, with values that don't
+			// depend on reads made outside of the transaction.
+			for j := 0; j < 100; j++ {
+				Transaction(func(tx *Tx) {
+					tx.Update(b, -j)
+					tx.Update(a, -j)
+				})
+			}
+		}()
+	}
+
+	for j := 1; j <= 100; j++ {
+		Transaction(func(tx *Tx) {
+			tx.Update(a, j)
+			tx.Update(b, j)
+		})
+	}
+
+	wg.Wait()
+	time.Sleep(10 * time.Millisecond)
+
+	for stream.HasNext() {
+		if val := stream.Next(); val != 0 {
+			t.Fatalf("Expecting 0 but got %#v\n", val)
+		}
+	}
+
+	if n := calls.Load(); n > 500 {
+		t.Fatalf("Expecting one recomputation per transaction but got %#v\n", n)
+	}
+}
+
+func TestTransactionNotifiesTogether(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		a := NewProperty(0)
+		b := NewProperty(0)
+		sa, sb := a.Observe(), b.Observe()
+
+		together := make(chan bool)
+		go func() {
+			<-sa.Changes()
+
+			commits.RLock()
+			defer commits.RUnlock()
+
+			together <- sb.HasNext()
+		}()
+
+		Transaction(func(tx *Tx) {
+			tx.Update(a, 1)
+			tx.Update(b, 1)
+		})
+
+		if !<-together {
+			t.Fatalf("Expecting both streams to be notified together\n")
+		}
+	}
+}
+
+func TestTransactionDerived(t *testing.T) {
+	for _, observed := range []bool{true, false} {
+		a := NewProperty(0)
+		d := Derive(func(values ...interface{}) interface{} {
+			return values[0]
+		}, a)
+
+		done := make(chan struct{})
+		if observed {
+			stream := d.Observe()
+			defer stream.Close()
+		} else {
+			// unobserved derived properties are recomputed when read.
+			go func() {
+				for {
+					select {
+					case <-done:
+						return
+					default:
+						d.Value()
+					}
+				}
+			}()
+		}
+
+		completed := make(chan struct{})
+		go func() {
+			defer close(completed)
+
+			start := time.Now()
+			for i := 0; time.Since(start) < time.Second; i++ {
+				Transaction(func(tx *Tx) {
+					tx.Update(a, i)
+					tx.Update(d, -i)
+				})
+				a.Update(i)
+			}
+		}()
+
+		select {
+		case <-completed:
+			close(done)
+		case <-time.After(10 * time.Second):
+			t.Fatalf("Expecting transactions updating derived properties to complete (observed: %v)\n", observed)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package wal
+
+import (
+	"time"
+
+	"github.com/botchris/go-observer/persist"
+)
+
+// Option handles configurable log options.
+type Option interface {
+	apply(*options)
+}
+
+type options struct {
+	codec         persist.Codec
+	segmentSize   int64
+	retentionSize int64
+	retentionAge  time.Duration
+	syncInterval  time.Duration
+	memoryLimit   int
+}
+
+type funcOption struct {
+	fn func(*options)
+}
+
+func (f *funcOption) apply(o *options) {
+	f.fn(o)
+}
+
+// WithCodec sets how values are serialized in the log, defaults to persist.JSON.
+func WithCodec(codec persist.Codec) Option {
+	return &funcOption{
+		fn: func(o *options) {
+			o.codec = codec
+		},
+	}
+}
+
+// WithSegmentSize sets the size in bytes a segment file grows to before a new one is started, defaults to 64MiB.
+func WithSegmentSize(size int64) Option {
+	return &funcOption{
+		fn: func(o *options) {
+			o.segmentSize = size
+		},
+	}
+}
+
+// WithRetentionSize discards the oldest segments once the log grows beyond the given size in bytes, by default
+// segments are retained regardless of their size. The segment being written to is always retained.
+func WithRetentionSize(size int64) Option {
+	return &funcOption{
+		fn: func(o *options) {
+			o.retentionSize = size
+		},
+	}
+}
+
+// WithRetentionAge discards the segments that have not been written to for the given duration, by default segments
+// are retained regardless of their age. The segment being written to is always retained. Expired segments are checked
+// for whenever a segment rotates and periodically in the background, at most every minute, so they are discarded even
+// when the property is no longer updated.
+func WithRetentionAge(d time.Duration) Option {
+	return &funcOption{
+		fn: func(o *options) {
+			o.retentionAge = d
+		},
+	}
+}
+
+// WithSyncInterval batches the fsync calls made to the log: updates are synced to disk every given interval instead
+// of one by one, trading durability of the most recent updates for throughput. By default every update is synced
+// before it is applied.
+func WithSyncInterval(d time.Duration) Option {
+	return &funcOption{
+		fn: func(o *options) {
+			o.syncInterval = d
+		},
+	}
+}
+
+// WithMemoryLimit sets how many of the most recent values are retained in memory, defaults to 1024. Older values
+// are read back from the log, see observer.WithHistoryLimit.
+func WithMemoryLimit(limit int) Option {
+	return &funcOption{
+		fn: func(o *options) {
+			o.memoryLimit = limit
+		},
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		codec:       persist.JSON,
+		segmentSize: 64 << 20,
+		memoryLimit: 1024,
+	}
+
+	for _, opt := range opts {
+		opt.apply(o)
+	}
+
+	o.memoryLimit = max(o.memoryLimit, 1)
+
+	return o
+}
@@ -0,0 +1,155 @@
+package wal
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/botchris/go-observer/persist"
+)
+
+const (
+	segmentExt = ".wal"
+
+	// headerSize is the size of the header preceding every record in a
+	// segment: the length of the encoded record and its CRC-32 checksum.
+	headerSize = 8
+
+	// maxRecordSize guards against reading garbage lengths from corrupt
+	// segments.
+	maxRecordSize = 1 << 30
+)
+
+// errCorrupt is returned when reading a record whose checksum doesn't match.
+var errCorrupt = errors.New("wal: corrupt record")
+
+// segment is a log file holding consecutive records, named after the sequence
+// number of its first record.
+type segment struct {
+	first uint64
+	path  string
+}
+
+// record is the persisted form of a property value.
+type record[T any] struct {
+	Seq     uint64
+	Time    time.Time
+	Value   T
+	Source  string            `json:",omitempty"`
+	Headers map[string]string `json:",omitempty"`
+}
+
+func segmentPath(dir string, first uint64) string {
+	return filepath.Join(dir, fmt.Sprintf("%020d%s", first, segmentExt))
+}
+
+// listSegments returns the segments found in dir, oldest first.
+func listSegments(dir string) ([]segment, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	segments := make([]segment, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, segmentExt) {
+			continue
+		}
+
+		first, err := strconv.ParseUint(strings.TrimSuffix(name, segmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		segments = append(segments, segment{first: first, path: filepath.Join(dir, name)})
+	}
+
+	slices.SortFunc(segments, func(a, b segment) int {
+		return cmp.Compare(a.first, b.first)
+	})
+
+	return segments, nil
+}
+
+// encodeRecord returns the given record encoded with its header.
+func encodeRecord[T any](codec persist.Codec, rec record[T]) ([]byte, error) {
+	buf := bytes.NewBuffer(make([]byte, headerSize))
+	if err := codec.NewEncoder(buf).Encode(rec); err != nil {
+		return nil, err
+	}
+
+	b := buf.Bytes()
+	payload := b[headerSize:]
+	binary.BigEndian.PutUint32(b[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(b[4:8], crc32.ChecksumIEEE(payload))
+
+	return b, nil
+}
+
+// readRecord reads the record stored at the given offset of a segment, along
+// with its size. It returns io.EOF if there is no complete record at offset.
+func readRecord[T any](codec persist.Codec, r io.ReaderAt, offset int64) (record[T], int64, error) {
+	var rec record[T]
+
+	header := make([]byte, headerSize)
+	if _, err := r.ReadAt(header, offset); err != nil {
+		return rec, 0, err
+	}
+
+	size := binary.BigEndian.Uint32(header[0:4])
+	if size > maxRecordSize {
+		return rec, 0, errCorrupt
+	}
+
+	payload := make([]byte, size)
+	if _, err := r.ReadAt(payload, offset+headerSize); err != nil {
+		return rec, 0, err
+	}
+
+	if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(header[4:8]) {
+		return rec, 0, errCorrupt
+	}
+
+	if err := codec.NewDecoder(bytes.NewReader(payload)).Decode(&rec); err != nil {
+		return rec, 0, errors.Join(errCorrupt, err)
+	}
+
+	return rec, headerSize + int64(size), nil
+}
+
+// lastRecord scans the given segment and returns its last complete record, if
+// any, along with the offset right after it. Anything after that offset is a
+// record that was cut short or corrupted by a crash.
+func lastRecord[T any](codec persist.Codec, path string) (rec record[T], end int64, found bool, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return rec, 0, false, err
+	}
+
+	defer f.Close()
+
+	for {
+		next, size, err := readRecord[T](codec, f, end)
+		if errors.Is(err, io.EOF) || errors.Is(err, errCorrupt) {
+			return rec, end, found, nil
+		}
+
+		if err != nil {
+			return rec, end, found, err
+		}
+
+		rec, found = next, true
+		end += size
+	}
+}
@@ -0,0 +1,458 @@
+package wal
+
+import (
+	"context"
+	"errors"
+	"io"
+	"iter"
+	"math"
+	"os"
+
+	"github.com/botchris/go-observer"
+)
+
+// ready is returned by the Changes method of streams reading from the log,
+// whose next value is always available.
+var ready = func() chan struct{} {
+	c := make(chan struct{})
+	close(c)
+	return c
+}()
+
+// stream reads the values of a property from its log until it catches up with
+// the values retained in memory, then it reads from an in-memory stream.
+//
+// While reading from the log the stream is never at the current value of the
+// property: it switches to memory as soon as its value is retained there,
+// which is always the case for the current value. So the next value is always
+// available in the log.
+type stream[T any] struct {
+	p *Property[T]
+
+	cur    record[T]
+	first  uint64
+	file   *os.File
+	offset int64
+	lagged *observer.Lagged
+
+	mem observer.StreamOf[T]
+}
+
+// observeLog returns a stream starting at the record with the given sequence
+// number read from the log.
+func (p *Property[T]) observeLog(seq uint64) (observer.StreamOf[T], error) {
+	s := &stream[T]{p: p}
+
+	p.mu.RLock()
+	segments := p.segments
+	p.mu.RUnlock()
+
+	if seq < segments[0].first {
+		return nil, observer.ErrSeqNotRetained
+	}
+
+	i := len(segments) - 1
+	for segments[i].first > seq {
+		i--
+	}
+
+	rec, ok := p.scan(segments[i], func(rec record[T]) bool {
+		return rec.Seq >= seq
+	})
+
+	if !ok || rec.Seq != seq {
+		return nil, observer.ErrSeqNotRetained
+	}
+
+	s.cur = rec
+	s.first = segments[i].first
+	s.attach()
+
+	return s, nil
+}
+
+// scan returns the first record of the given segment satisfying the predicate,
+// or its first record if predicate is nil.
+func (p *Property[T]) scan(s segment, predicate func(record[T]) bool) (record[T], bool) {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return record[T]{}, false
+	}
+
+	defer f.Close()
+
+	offset := int64(0)
+	for {
+		rec, size, err := readRecord[T](p.opts.codec, f, offset)
+		if err != nil {
+			return rec, false
+		}
+
+		if predicate == nil || predicate(rec) {
+			return rec, true
+		}
+
+		offset += size
+	}
+}
+
+// attach switches this stream to memory if its current value is retained
+// there, and reports whether it did so.
+func (s *stream[T]) attach() bool {
+	// hold the lock so the records read from the log have all been applied
+	// in memory.
+	s.p.mu.RLock()
+	defer s.p.mu.RUnlock()
+
+	if s.p.head-s.cur.Seq >= uint64(s.p.opts.memoryLimit) {
+		return false
+	}
+
+	mem, err := s.p.mem.ObserveAt(s.cur.Seq)
+	if err != nil {
+		return false
+	}
+
+	s.detach()
+	s.mem = mem
+
+	return true
+}
+
+// detach closes the segment file being read.
+func (s *stream[T]) detach() {
+	if s.file != nil {
+		s.file.Close()
+		s.file = nil
+	}
+}
+
+// advance reads the next record from the log, jumping to the oldest retained
+// one if the segments that came before have been discarded.
+func (s *stream[T]) advance() {
+	s.lagged = nil
+
+	for {
+		if s.file == nil && !s.open() {
+			s.fallback()
+			return
+		}
+
+		rec, size, err := readRecord[T](s.p.opts.codec, s.file, s.offset)
+		if errors.Is(err, io.EOF) && s.next() {
+			continue
+		}
+
+		if err != nil {
+			s.fallback()
+			return
+		}
+
+		s.offset += size
+		if rec.Seq <= s.cur.Seq {
+			continue
+		}
+
+		if skipped := rec.Seq - s.cur.Seq - 1; skipped > 0 {
+			s.lagged = &observer.Lagged{Skipped: int(skipped)}
+		}
+
+		s.cur = rec
+		s.attach()
+
+		return
+	}
+}
+
+// open opens the segment holding the record that follows the current one, or
+// the oldest segment if it has been discarded.
+func (s *stream[T]) open() bool {
+	for {
+		s.p.mu.RLock()
+		segments := s.p.segments
+		s.p.mu.RUnlock()
+
+		i := len(segments) - 1
+		for i > 0 && segments[i].first > s.cur.Seq+1 {
+			i--
+		}
+
+		if segments[i].first != s.first {
+			s.first = segments[i].first
+			s.offset = 0
+		}
+
+		f, err := os.Open(segments[i].path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			return false
+		}
+
+		s.file = f
+
+		return true
+	}
+}
+
+// next moves on to the segment following the one being read, if any.
+func (s *stream[T]) next() bool {
+	s.p.mu.RLock()
+	defer s.p.mu.RUnlock()
+
+	for _, segment := range s.p.segments {
+		if segment.first > s.first {
+			s.detach()
+			s.first = segment.first
+			s.offset = 0
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// rewind goes back to reading from the log when the in-memory stream skipped
+// values because it fell behind the memory limit, the log still has them.
+func (s *stream[T]) rewind(seq uint64) {
+	if s.mem.Lagged() == nil {
+		return
+	}
+
+	s.mem.Close()
+	s.mem = nil
+	s.cur = record[T]{Seq: seq}
+	s.offset = 0
+	s.advance()
+}
+
+// fallback switches this stream to the oldest value retained in memory when
+// the log cannot be read.
+func (s *stream[T]) fallback() {
+	s.detach()
+	s.mem = s.p.mem.ObserveFrom(math.MaxInt)
+
+	if seq := s.mem.Seq(); seq > s.cur.Seq+1 {
+		s.lagged = &observer.Lagged{Skipped: int(seq - s.cur.Seq - 1)}
+	}
+}
+
+func (s *stream[T]) Value() T {
+	if s.mem != nil {
+		return s.mem.Value()
+	}
+
+	return s.cur.Value
+}
+
+func (s *stream[T]) Changes() chan struct{} {
+	if s.mem != nil {
+		return s.mem.Changes()
+	}
+
+	return ready
+}
+
+func (s *stream[T]) Next() T {
+	if s.mem != nil {
+		prev := s.mem.Seq()
+		s.lagged = nil
+		s.mem.Next()
+		s.rewind(prev)
+
+		return s.Value()
+	}
+
+	s.advance()
+
+	return s.Value()
+}
+
+func (s *stream[T]) HasNext() bool {
+	if s.mem != nil {
+		return s.mem.HasNext()
+	}
+
+	return true
+}
+
+// Latest skips to the current value of the property, see
+// observer.StreamOf.Latest. Streams reading from the log switch to memory
+// right away.
+func (s *stream[T]) Latest() int {
+	s.lagged = nil
+	if s.mem != nil {
+		return s.mem.Latest()
+	}
+
+	s.detach()
+	s.mem = s.p.mem.Observe()
+
+	if seq := s.mem.Seq(); seq > s.cur.Seq+1 {
+		return int(seq - s.cur.Seq - 1)
+	}
+
+	return 0
+}
+
+func (s *stream[T]) Drain(max int) []T {
+	var values []T
+	skipped := 0
+
+	for (max <= 0 || len(values) < max) && s.HasNext() {
+		value := s.Next()
+		if lagged := s.Lagged(); lagged != nil {
+			skipped += lagged.Skipped
+		}
+
+		if s.Ended() {
+			break
+		}
+
+		values = append(values, value)
+	}
+
+	s.lagged = nil
+	if skipped > 0 {
+		s.lagged = &observer.Lagged{Skipped: skipped}
+	}
+
+	return values
+}
+
+func (s *stream[T]) WaitNext() T {
+	if s.mem != nil {
+		prev := s.mem.Seq()
+		s.lagged = nil
+		s.mem.WaitNext()
+		s.rewind(prev)
+
+		return s.Value()
+	}
+
+	return s.Next()
+}
+
+func (s *stream[T]) WaitNextContext(ctx context.Context) (T, error) {
+	if s.mem != nil {
+		prev := s.mem.Seq()
+		s.lagged = nil
+		if _, err := s.mem.WaitNextContext(ctx); err != nil {
+			var zero T
+			return zero, err
+		}
+
+		s.rewind(prev)
+
+		return s.Value(), nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return s.Next(), nil
+}
+
+func (s *stream[T]) WaitUntil(ctx context.Context, predicate func(T) bool) (T, error) {
+	value := s.Value()
+	for {
+		if s.Ended() {
+			if err := s.Err(); err != nil {
+				return value, err
+			}
+
+			return value, io.EOF
+		}
+
+		if predicate(value) {
+			return value, nil
+		}
+
+		var err error
+		if value, err = s.WaitNextContext(ctx); err != nil {
+			return value, err
+		}
+	}
+}
+
+func (s *stream[T]) Values(ctx context.Context) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for {
+			value, err := s.WaitNextContext(ctx)
+			if err != nil || s.Ended() || !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+func (s *stream[T]) Clone() observer.StreamOf[T] {
+	if s.mem != nil {
+		return &stream[T]{p: s.p, cur: s.cur, mem: s.mem.Clone()}
+	}
+
+	return &stream[T]{p: s.p, cur: s.cur, first: s.first, offset: s.offset}
+}
+
+func (s *stream[T]) Ended() bool {
+	return s.mem != nil && s.mem.Ended()
+}
+
+func (s *stream[T]) Err() error {
+	if s.mem != nil {
+		return s.mem.Err()
+	}
+
+	return nil
+}
+
+// Meta describes the update that set the current value of this stream, see
+// observer.StreamOf.Meta. Values read from the log report the time they were
+// written to it.
+func (s *stream[T]) Meta() observer.Meta {
+	if s.mem != nil {
+		return s.mem.Meta()
+	}
+
+	return observer.Meta{
+		Source:  s.cur.Source,
+		Headers: s.cur.Headers,
+		Time:    s.cur.Time,
+		Seq:     s.cur.Seq,
+	}
+}
+
+func (s *stream[T]) Seq() uint64 {
+	if s.mem != nil {
+		return s.mem.Seq()
+	}
+
+	return s.cur.Seq
+}
+
+// Lagged reports the values skipped by the last call to Next, either because
+// they were discarded from the log or from memory.
+func (s *stream[T]) Lagged() *observer.Lagged {
+	if s.lagged == nil && s.mem != nil {
+		return s.mem.Lagged()
+	}
+
+	return s.lagged
+}
+
+// Close detaches this stream from the log, it then behaves as a closed
+// observer.StreamOf.
+func (s *stream[T]) Close() {
+	s.detach()
+
+	if s.mem == nil {
+		s.mem = s.p.mem.Observe()
+	}
+
+	s.mem.Close()
+}
@@ -0,0 +1,527 @@
+// Package wal provides properties backed by a write-ahead log: every update is
+// appended to a segment file on disk before it is applied, so the history of
+// the property survives crashes and restarts, and streams can start at any
+// value still retained in the log, not just the ones retained in memory.
+package wal
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/botchris/go-observer"
+)
+
+// Property is a property backed by a write-ahead log stored in a directory. It
+// implements observer.PropertyOf, streams are read from memory while they are
+// close enough to the current value and from the log otherwise.
+//
+// If writing to the log fails, the property fails with the write error, see
+// observer.PropertyOf.Fail.
+type Property[T any] struct {
+	mem  observer.PropertyOf[T]
+	opts *options
+	dir  string
+
+	mu       sync.RWMutex
+	segments []segment
+	active   *os.File
+	size     int64
+	head     uint64
+	dirty    bool
+	closed   bool
+	stop     chan struct{}
+}
+
+var _ observer.PropertyOf[interface{}] = (*Property[interface{}])(nil)
+
+// Open opens the log stored in the given directory, creating it if needed, and
+// returns a property holding the last value written to it. The property holds
+// the given initial value when the log is empty. Records that were cut short
+// by a crash are discarded.
+func Open[T any](dir string, value T, opts ...Option) (*Property[T], error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	segments, err := listSegments(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Property[T]{
+		opts: newOptions(opts),
+		dir:  dir,
+		stop: make(chan struct{}),
+	}
+
+	head, err := p.recover(segments)
+	if err != nil {
+		return nil, err
+	}
+
+	if head == nil {
+		head = &record[T]{Time: time.Now(), Value: value}
+		if err := p.rotate(0); err != nil {
+			return nil, err
+		}
+
+		if err := p.write(*head); err != nil {
+			p.active.Close()
+			return nil, err
+		}
+	}
+
+	p.head = head.Seq
+	p.mem = observer.NewPropertyOf(head.Value,
+		observer.WithStartSeq(head.Seq),
+		observer.WithHistoryLimit(p.opts.memoryLimit),
+	)
+
+	if p.opts.syncInterval > 0 {
+		go p.syncer()
+	}
+
+	if p.opts.retentionAge > 0 {
+		go p.retainer()
+	}
+
+	return p, nil
+}
+
+// recover finds the last record of the given segments and opens its segment
+// for writing, dropping whatever follows it. It returns nil if there are no
+// records at all.
+func (p *Property[T]) recover(segments []segment) (*record[T], error) {
+	for i := len(segments) - 1; i >= 0; i-- {
+		last, end, found, err := lastRecord[T](p.opts.codec, segments[i].path)
+		if err != nil {
+			return nil, err
+		}
+
+		if !found {
+			if err := os.Remove(segments[i].path); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		f, err := os.OpenFile(segments[i].path, os.O_WRONLY, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := f.Truncate(end); err != nil {
+			f.Close()
+			return nil, err
+		}
+
+		if _, err := f.Seek(end, io.SeekStart); err != nil {
+			f.Close()
+			return nil, err
+		}
+
+		p.segments = segments[:i+1]
+		p.active = f
+		p.size = end
+
+		return &last, nil
+	}
+
+	return nil, nil
+}
+
+func (p *Property[T]) Value() T {
+	return p.mem.Value()
+}
+
+func (p *Property[T]) Update(values ...T) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, value := range values {
+		if any(value) == io.EOF {
+			p.mem.End()
+			return
+		}
+
+		if !p.apply(value, observer.Meta{}) {
+			return
+		}
+	}
+}
+
+// UpdateWithMeta is like Update but the source and headers of meta are written
+// to the log along with the value, see observer.PropertyOf.UpdateWithMeta.
+func (p *Property[T]) UpdateWithMeta(value T, meta observer.Meta) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if any(value) == io.EOF {
+		p.mem.End()
+		return
+	}
+
+	p.apply(value, meta)
+}
+
+func (p *Property[T]) UpdateFunc(fn func(old T) T) T {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.ended() {
+		p.apply(fn(p.mem.Value()), observer.Meta{})
+	}
+
+	return p.mem.Value()
+}
+
+func (p *Property[T]) CompareAndSwap(old, new T) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.ended() || any(p.mem.Value()) != any(old) {
+		return false
+	}
+
+	return p.apply(new, observer.Meta{})
+}
+
+// apply appends the given value and the source and headers of meta to the log,
+// and then updates the in-memory property. Must be called while holding the
+// lock, it reports whether the value was applied.
+func (p *Property[T]) apply(value T, meta observer.Meta) bool {
+	if p.ended() {
+		return false
+	}
+
+	rec := record[T]{
+		Seq:     p.head + 1,
+		Time:    time.Now(),
+		Value:   value,
+		Source:  meta.Source,
+		Headers: meta.Headers,
+	}
+
+	if err := p.write(rec); err != nil {
+		p.mem.Fail(err)
+		return false
+	}
+
+	p.head = rec.Seq
+	p.mem.UpdateWithMeta(value, meta)
+
+	return true
+}
+
+// write appends the given record to the active segment, starting a new one
+// first if it is full. Must be called while holding the lock.
+func (p *Property[T]) write(rec record[T]) error {
+	b, err := encodeRecord(p.opts.codec, rec)
+	if err != nil {
+		return err
+	}
+
+	if p.size >= p.opts.segmentSize {
+		if err := p.rotate(rec.Seq); err != nil {
+			return err
+		}
+	}
+
+	n, err := p.active.Write(b)
+	p.size += int64(n)
+	if err != nil {
+		return err
+	}
+
+	if p.opts.syncInterval > 0 {
+		p.dirty = true
+		return nil
+	}
+
+	return p.active.Sync()
+}
+
+// rotate closes the active segment and starts a new one whose first record
+// will have the given sequence number, then discards the segments exceeding
+// the retention limits. Must be called while holding the lock.
+func (p *Property[T]) rotate(first uint64) error {
+	if p.active != nil {
+		if err := p.active.Sync(); err != nil {
+			return err
+		}
+
+		if err := p.active.Close(); err != nil {
+			return err
+		}
+	}
+
+	path := segmentPath(p.dir, first)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+
+	p.segments = append(p.segments, segment{first: first, path: path})
+	p.active = f
+	p.size = 0
+	p.dirty = false
+
+	return p.retain()
+}
+
+// retain discards the oldest segments while they exceed the retention limits,
+// must be called while holding the lock.
+func (p *Property[T]) retain() error {
+	if p.opts.retentionSize <= 0 && p.opts.retentionAge <= 0 {
+		return nil
+	}
+
+	infos := make([]os.FileInfo, len(p.segments))
+	total := int64(0)
+	for i, s := range p.segments {
+		info, err := os.Stat(s.path)
+		if err != nil {
+			return err
+		}
+
+		infos[i] = info
+		total += info.Size()
+	}
+
+	for len(p.segments) > 1 {
+		oversized := p.opts.retentionSize > 0 && total > p.opts.retentionSize
+		expired := p.opts.retentionAge > 0 && time.Since(infos[0].ModTime()) > p.opts.retentionAge
+		if !oversized && !expired {
+			break
+		}
+
+		if err := os.Remove(p.segments[0].path); err != nil {
+			return err
+		}
+
+		total -= infos[0].Size()
+		infos = infos[1:]
+		p.segments = p.segments[1:]
+	}
+
+	return nil
+}
+
+// Sync commits the updates written to the log to stable storage, only needed
+// when syncing is batched with WithSyncInterval.
+func (p *Property[T]) Sync() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.sync()
+}
+
+// sync must be called while holding the lock.
+func (p *Property[T]) sync() error {
+	if !p.dirty || p.closed {
+		return nil
+	}
+
+	p.dirty = false
+
+	return p.active.Sync()
+}
+
+// syncer periodically syncs the log until it is closed.
+func (p *Property[T]) syncer() {
+	ticker := time.NewTicker(p.opts.syncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			if err := p.Sync(); err != nil {
+				p.mem.Fail(err)
+			}
+		}
+	}
+}
+
+// retainer periodically discards the expired segments until the log is closed,
+// so they are discarded even when no segment rotates.
+func (p *Property[T]) retainer() {
+	ticker := time.NewTicker(min(p.opts.retentionAge, time.Minute))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			if err := p.expire(); err != nil {
+				p.mem.Fail(err)
+			}
+		}
+	}
+}
+
+// expire discards the segments exceeding the retention limits, unless the log
+// has been closed.
+func (p *Property[T]) expire() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil
+	}
+
+	return p.retain()
+}
+
+// Close syncs and closes the log, and ends the property. Streams can still
+// read the values that came before.
+func (p *Property[T]) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil
+	}
+
+	err := p.sync()
+	p.closed = true
+	close(p.stop)
+	p.mem.End()
+
+	return errors.Join(err, p.active.Close())
+}
+
+// ended reports whether the property has ended, either because it has been
+// closed or because writing to the log failed.
+func (p *Property[T]) ended() bool {
+	select {
+	case <-p.mem.Done():
+		return true
+	default:
+		return p.closed
+	}
+}
+
+// Observe returns a newly created stream starting at the current value. If it
+// falls behind the memory limit, it reads the values it missed from the log.
+func (p *Property[T]) Observe() observer.StreamOf[T] {
+	return &stream[T]{p: p, mem: p.mem.Observe()}
+}
+
+// ObserveFrom returns a newly created stream that starts n values before the
+// current one, reading them from the log when they are not retained in memory.
+// It starts at the oldest value retained in the log when it holds less than n
+// previous values.
+func (p *Property[T]) ObserveFrom(n int) observer.StreamOf[T] {
+	p.mu.RLock()
+	seq := p.segments[0].first
+	if n >= 0 && p.head-seq > uint64(n) {
+		seq = p.head - uint64(n)
+	}
+	p.mu.RUnlock()
+
+	s, err := p.ObserveAt(seq)
+	if err != nil {
+		return &stream[T]{p: p, mem: p.mem.ObserveFrom(n)}
+	}
+
+	return s
+}
+
+// ObserveSince returns a newly created stream that starts at the oldest value
+// retained in the log this property was updated to at or after t. It starts at
+// the current value if there is no such value.
+func (p *Property[T]) ObserveSince(t time.Time) observer.StreamOf[T] {
+	seq, ok := p.seqSince(t)
+	if !ok {
+		return p.Observe()
+	}
+
+	s, err := p.ObserveAt(seq)
+	if err != nil {
+		return &stream[T]{p: p, mem: p.mem.ObserveSince(t)}
+	}
+
+	return s
+}
+
+// ObserveAt returns a newly created stream that starts at the value with the
+// given sequence number, reading it from the log if it is no longer retained
+// in memory. It returns observer.ErrSeqNotRetained if the value has been
+// discarded from the log as well.
+func (p *Property[T]) ObserveAt(seq uint64) (observer.StreamOf[T], error) {
+	mem, err := p.mem.ObserveAt(seq)
+	if err == nil {
+		return &stream[T]{p: p, mem: mem}, nil
+	}
+
+	if !errors.Is(err, observer.ErrSeqNotRetained) {
+		return nil, err
+	}
+
+	return p.observeLog(seq)
+}
+
+func (p *Property[T]) WaitFor(ctx context.Context, predicate func(T) bool) (T, error) {
+	return p.mem.WaitFor(ctx, predicate)
+}
+
+// End marks this property as "ended", the log is left open until Close is
+// called.
+func (p *Property[T]) End() {
+	p.mem.End()
+}
+
+func (p *Property[T]) Fail(err error) {
+	p.mem.Fail(err)
+}
+
+func (p *Property[T]) Done() <-chan struct{} {
+	return p.mem.Done()
+}
+
+func (p *Property[T]) Err() error {
+	return p.mem.Err()
+}
+
+// Stats returns a snapshot of how this property is being observed, streams
+// reading from the log are only accounted for once they catch up with the
+// values retained in memory.
+func (p *Property[T]) Stats() observer.Stats {
+	return p.mem.Stats()
+}
+
+// seqSince returns the sequence number of the oldest record in the log written
+// at or after t, if any.
+func (p *Property[T]) seqSince(t time.Time) (uint64, bool) {
+	p.mu.RLock()
+	segments := p.segments
+	p.mu.RUnlock()
+
+	// look for the newest segment starting before t, the record is either in
+	// that segment or it's the first one of the next segment.
+	start := 0
+	for i := len(segments) - 1; i > 0; i-- {
+		if first, ok := p.scan(segments[i], nil); ok && first.Time.Before(t) {
+			start = i
+			break
+		}
+	}
+
+	for _, s := range segments[start:] {
+		if rec, ok := p.scan(s, func(rec record[T]) bool {
+			return !rec.Time.Before(t)
+		}); ok {
+			return rec.Seq, true
+		}
+	}
+
+	return 0, false
+}
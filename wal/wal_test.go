@@ -0,0 +1,356 @@
+package wal_test
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/botchris/go-observer"
+	"github.com/botchris/go-observer/persist"
+	"github.com/botchris/go-observer/wal"
+	"github.com/stretchr/testify/require"
+)
+
+type point struct {
+	X, Y int
+}
+
+func segments(t *testing.T, dir string) []string {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.wal"))
+	require.NoError(t, err)
+
+	return matches
+}
+
+func TestOpen(t *testing.T) {
+	t.Run("GIVEN an empty log WHEN opened THEN the initial value is used", func(t *testing.T) {
+		prop, err := wal.Open(t.TempDir(), 10)
+		require.NoError(t, err)
+		defer prop.Close()
+
+		stream := prop.Observe()
+		require.Equal(t, 10, stream.Value())
+		require.Equal(t, uint64(0), stream.Seq())
+	})
+
+	t.Run("GIVEN a log WHEN reopened THEN the last value and its history are restored", func(t *testing.T) {
+		dir := t.TempDir()
+
+		prop, err := wal.Open(dir, point{}, wal.WithCodec(persist.Gob))
+		require.NoError(t, err)
+
+		prop.Update(point{1, 1}, point{2, 2})
+		require.NoError(t, prop.Close())
+
+		prop, err = wal.Open(dir, point{}, wal.WithCodec(persist.Gob))
+		require.NoError(t, err)
+		defer prop.Close()
+
+		require.Equal(t, point{2, 2}, prop.Value())
+
+		stream, err := prop.ObserveAt(0)
+		require.NoError(t, err)
+		require.Equal(t, point{}, stream.Value())
+		require.Equal(t, point{1, 1}, stream.Next())
+		require.Equal(t, point{2, 2}, stream.Next())
+
+		prop.Update(point{3, 3})
+		require.Equal(t, point{3, 3}, stream.WaitNext())
+		require.Equal(t, uint64(3), stream.Seq())
+	})
+
+	t.Run("GIVEN updates with meta WHEN reopened THEN their source and headers are restored", func(t *testing.T) {
+		dir := t.TempDir()
+
+		prop, err := wal.Open(dir, 0)
+		require.NoError(t, err)
+
+		prop.UpdateWithMeta(1, observer.Meta{Source: "writer-1", Headers: map[string]string{"trace-id": "abc"}})
+		prop.Update(2)
+
+		stream, err := prop.ObserveAt(1)
+		require.NoError(t, err)
+		require.Equal(t, "writer-1", stream.Meta().Source)
+		require.NoError(t, prop.Close())
+
+		prop, err = wal.Open(dir, 0, wal.WithMemoryLimit(1))
+		require.NoError(t, err)
+		defer prop.Close()
+
+		stream, err = prop.ObserveAt(1)
+		require.NoError(t, err)
+
+		meta := stream.Meta()
+		require.Equal(t, "writer-1", meta.Source)
+		require.Equal(t, map[string]string{"trace-id": "abc"}, meta.Headers)
+		require.Equal(t, uint64(1), meta.Seq)
+		require.False(t, meta.Time.IsZero())
+
+		stream.Next()
+		require.Empty(t, stream.Meta().Source)
+	})
+
+	t.Run("GIVEN a log with a record cut short WHEN reopened THEN the record is discarded", func(t *testing.T) {
+		dir := t.TempDir()
+
+		prop, err := wal.Open(dir, 0)
+		require.NoError(t, err)
+
+		prop.Update(1, 2)
+		require.NoError(t, prop.Close())
+
+		f, err := os.OpenFile(segments(t, dir)[0], os.O_WRONLY|os.O_APPEND, 0)
+		require.NoError(t, err)
+		_, err = f.Write([]byte{0, 0, 0, 42, 1, 2})
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+
+		prop, err = wal.Open(dir, 0)
+		require.NoError(t, err)
+		defer prop.Close()
+
+		require.Equal(t, 2, prop.Value())
+
+		prop.Update(3)
+		stream, err := prop.ObserveAt(2)
+		require.NoError(t, err)
+		require.Equal(t, 3, stream.Next())
+	})
+
+	t.Run("GIVEN batched syncs WHEN closed THEN every update is persisted", func(t *testing.T) {
+		dir := t.TempDir()
+
+		prop, err := wal.Open(dir, 0, wal.WithSyncInterval(time.Hour))
+		require.NoError(t, err)
+
+		for i := 1; i <= 100; i++ {
+			prop.Update(i)
+		}
+
+		require.NoError(t, prop.Sync())
+		require.NoError(t, prop.Close())
+		require.NoError(t, prop.Close())
+
+		prop, err = wal.Open(dir, 0)
+		require.NoError(t, err)
+		defer prop.Close()
+
+		require.Equal(t, 100, prop.Value())
+	})
+}
+
+func TestProperty(t *testing.T) {
+	t.Run("GIVEN values beyond the memory limit WHEN observed at an old sequence THEN they are read from the log", func(t *testing.T) {
+		prop, err := wal.Open(t.TempDir(), 0, wal.WithMemoryLimit(2), wal.WithSegmentSize(64))
+		require.NoError(t, err)
+		defer prop.Close()
+
+		for i := 1; i <= 10; i++ {
+			prop.Update(i)
+		}
+
+		stream, err := prop.ObserveAt(1)
+		require.NoError(t, err)
+		require.Equal(t, 1, stream.Value())
+
+		for i := 2; i <= 10; i++ {
+			require.True(t, stream.HasNext())
+			require.Equal(t, i, stream.Next())
+			require.Nil(t, stream.Lagged())
+		}
+
+		require.False(t, stream.HasNext())
+
+		prop.Update(11)
+		require.Equal(t, 11, stream.WaitNext())
+	})
+
+	t.Run("GIVEN a slow stream WHEN it falls behind the memory limit THEN skipped values are read from the log", func(t *testing.T) {
+		prop, err := wal.Open(t.TempDir(), 0, wal.WithMemoryLimit(2))
+		require.NoError(t, err)
+		defer prop.Close()
+
+		stream := prop.Observe()
+		for i := 1; i <= 10; i++ {
+			prop.Update(i)
+		}
+
+		values := make([]int, 0)
+		for stream.HasNext() {
+			values = append(values, stream.Next())
+			require.Nil(t, stream.Lagged())
+		}
+
+		require.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, values)
+	})
+
+	t.Run("GIVEN a property WHEN observed from n values back THEN values are read from the log", func(t *testing.T) {
+		prop, err := wal.Open(t.TempDir(), 0, wal.WithMemoryLimit(1))
+		require.NoError(t, err)
+		defer prop.Close()
+
+		prop.Update(1, 2, 3, 4)
+
+		require.Equal(t, 2, prop.ObserveFrom(2).Value())
+		require.Equal(t, 0, prop.ObserveFrom(math.MaxInt).Value())
+	})
+
+	t.Run("GIVEN a property WHEN observed since a time THEN values are read from the log", func(t *testing.T) {
+		prop, err := wal.Open(t.TempDir(), 0, wal.WithMemoryLimit(1), wal.WithSegmentSize(64))
+		require.NoError(t, err)
+		defer prop.Close()
+
+		prop.Update(1, 2)
+		time.Sleep(10 * time.Millisecond)
+		since := time.Now()
+		prop.Update(3, 4, 5)
+
+		stream := prop.ObserveSince(since)
+		require.Equal(t, 3, stream.Value())
+		require.Equal(t, 4, stream.Next())
+
+		require.Equal(t, 5, prop.ObserveSince(time.Now()).Value())
+	})
+
+	t.Run("GIVEN a stream reading from the log WHEN skipping to the latest value THEN it reads from memory", func(t *testing.T) {
+		prop, err := wal.Open(t.TempDir(), 0, wal.WithMemoryLimit(2))
+		require.NoError(t, err)
+		defer prop.Close()
+
+		prop.Update(1, 2, 3, 4, 5)
+
+		stream, err := prop.ObserveAt(1)
+		require.NoError(t, err)
+		require.Equal(t, []int{2, 3}, stream.Drain(2))
+		require.Equal(t, 1, stream.Latest())
+		require.Equal(t, 5, stream.Value())
+
+		prop.Update(6, 7)
+		require.Equal(t, []int{6, 7}, stream.Drain(0))
+		require.Nil(t, stream.Lagged())
+	})
+
+	t.Run("GIVEN a closed property WHEN updated THEN the update is ignored", func(t *testing.T) {
+		prop, err := wal.Open(t.TempDir(), 0)
+		require.NoError(t, err)
+
+		require.NoError(t, prop.Close())
+		prop.Update(1)
+
+		require.Equal(t, 0, prop.Value())
+		require.NoError(t, prop.Err())
+
+		_, err = prop.WaitFor(context.Background(), func(int) bool {
+			return false
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("GIVEN a stream reading from the log WHEN closed THEN it ends", func(t *testing.T) {
+		prop, err := wal.Open(t.TempDir(), 0, wal.WithMemoryLimit(1))
+		require.NoError(t, err)
+		defer prop.Close()
+
+		prop.Update(1, 2, 3)
+
+		stream, err := prop.ObserveAt(0)
+		require.NoError(t, err)
+
+		clone := stream.Clone()
+		stream.Close()
+
+		require.True(t, stream.Ended())
+		require.Equal(t, observer.ErrClosed, stream.Err())
+		require.Equal(t, 1, clone.Next())
+	})
+}
+
+func TestRetention(t *testing.T) {
+	t.Run("GIVEN a size retention WHEN segments rotate THEN oldest segments are discarded", func(t *testing.T) {
+		dir := t.TempDir()
+		prop, err := wal.Open(dir, 0, wal.WithMemoryLimit(1), wal.WithSegmentSize(64), wal.WithRetentionSize(256))
+		require.NoError(t, err)
+		defer prop.Close()
+
+		for i := 1; i <= 100; i++ {
+			prop.Update(i)
+		}
+
+		require.LessOrEqual(t, len(segments(t, dir)), 6)
+
+		_, err = prop.ObserveAt(0)
+		require.Equal(t, observer.ErrSeqNotRetained, err)
+
+		stream := prop.ObserveFrom(math.MaxInt)
+		require.Greater(t, stream.Seq(), uint64(0))
+
+		values := []int{stream.Value()}
+		for stream.HasNext() {
+			values = append(values, stream.Next())
+		}
+
+		require.Equal(t, 100, values[len(values)-1])
+		require.Len(t, values, 100-values[0]+1)
+	})
+
+	t.Run("GIVEN a stream reading from the log WHEN its segments are discarded THEN it reports the skipped values", func(t *testing.T) {
+		dir := t.TempDir()
+		prop, err := wal.Open(dir, 0, wal.WithMemoryLimit(1), wal.WithSegmentSize(64), wal.WithRetentionSize(256))
+		require.NoError(t, err)
+		defer prop.Close()
+
+		prop.Update(1, 2, 3)
+
+		stream, err := prop.ObserveAt(0)
+		require.NoError(t, err)
+
+		for i := 4; i <= 100; i++ {
+			prop.Update(i)
+		}
+
+		next := stream.Next()
+		require.NotNil(t, stream.Lagged())
+		require.Equal(t, next-1, stream.Lagged().Skipped)
+	})
+
+	t.Run("GIVEN an age retention WHEN segments rotate THEN expired segments are discarded", func(t *testing.T) {
+		dir := t.TempDir()
+		prop, err := wal.Open(dir, 0, wal.WithSegmentSize(64), wal.WithRetentionAge(50*time.Millisecond))
+		require.NoError(t, err)
+		defer prop.Close()
+
+		for i := 1; i <= 20; i++ {
+			prop.Update(i)
+		}
+
+		before := len(segments(t, dir))
+		require.Greater(t, before, 2)
+
+		time.Sleep(100 * time.Millisecond)
+		prop.Update(21, 22, 23)
+
+		require.Less(t, len(segments(t, dir)), before)
+	})
+
+	t.Run("GIVEN an age retention WHEN the property is no longer updated THEN expired segments are discarded", func(t *testing.T) {
+		dir := t.TempDir()
+		prop, err := wal.Open(dir, 0, wal.WithSegmentSize(64), wal.WithRetentionAge(50*time.Millisecond))
+		require.NoError(t, err)
+		defer prop.Close()
+
+		for i := 1; i <= 20; i++ {
+			prop.Update(i)
+		}
+
+		require.Greater(t, len(segments(t, dir)), 2)
+		require.Eventually(t, func() bool {
+			return len(segments(t, dir)) == 1
+		}, 5*time.Second, 10*time.Millisecond)
+
+		require.NoError(t, prop.Err())
+		require.Equal(t, 20, prop.Value())
+	})
+}